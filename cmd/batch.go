@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/eliheady/wtutf/inspect"
+	"github.com/spf13/cobra"
+)
+
+// BatchSummary is emitted after the last record in batch mode
+type BatchSummary struct {
+	Records        int `json:"records"`
+	PunycodeErrors int `json:"punycode_errors"`
+	InvalidUTF8    int `json:"invalid_utf8"`
+	MixedScript    int `json:"mixed_script"`
+	Confusable     int `json:"confusable"`
+	Bidi           int `json:"bidi"`
+	Watchlist      int `json:"watchlist,omitempty"`
+	Policy         int `json:"policy,omitempty"`
+}
+
+// openBatchInput returns the reader batch records are read from: the --file
+// flag or stdin. The returned func closes the file, if one was opened.
+func openBatchInput(cmd *cobra.Command, args []string) (io.Reader, func(), error) {
+	if check, _ := cmd.Flags().GetBool("check"); check {
+		return nil, nil, errors.New("--check cannot be combined with --batch")
+	}
+	if len(args) > 0 && args[0] != "-" {
+		return nil, nil, errors.New("--batch reads records from --file or stdin, not from an argument")
+	}
+	path, _ := cmd.Flags().GetString("file")
+	if path == "" {
+		return cmd.InOrStdin(), func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// runBatch analyzes each newline- or NUL-delimited record read from r and
+// writes the results to the command's output as they are produced. With
+// --json each result is a single line of JSON (NDJSON) and the final line is
+// a {"summary": ...} object. Empty records are skipped. The watchlist and
+// policy may be nil. The exit status combines the statuses of every record.
+func runBatch(cmd *cobra.Command, r io.Reader, wl *inspect.Watchlist, pol *inspect.Policy) (status int, err error) {
+	flags := cmd.Flags()
+	showRanges, _ := flags.GetBool("show-ranges")
+	table, _ := flags.GetBool("table")
+	properties, _ := flags.GetBool("properties")
+	table = table || properties
+	jsonOut, _ := flags.GetBool("json")
+	ambiguousWide, _ := flags.GetBool("ambiguous-wide")
+	accept, err := checkLevelFlag(cmd)
+	if err != nil {
+		return exitError, err
+	}
+	failOn, _ := failOnFlag(cmd)
+	analyzer := newAnalyzer(cmd, wl, pol)
+	// the summary counts these whatever --checks selects
+	confusable, _ := inspect.LookupCheck("confusable")
+	bidi, _ := inspect.LookupCheck("bidi")
+	summaryChecks := []inspect.Check{inspect.MixedScriptCheck(accept), confusable, bidi}
+	delim := byte('\n')
+	if nullDelim, _ := flags.GetBool("null"); nullDelim {
+		delim = 0
+	}
+
+	w := bufio.NewWriter(cmd.OutOrStdout())
+	defer w.Flush()
+	enc := json.NewEncoder(w)
+
+	var summary BatchSummary
+	br := bufio.NewReader(r)
+	for {
+		record, readErr := br.ReadString(delim)
+		if readErr != nil && readErr != io.EOF {
+			return exitError, readErr
+		}
+		if len(record) > 0 && record[len(record)-1] == delim {
+			record = record[:len(record)-1]
+			if delim == '\n' {
+				// lines from Windows end in CRLF
+				record = strings.TrimSuffix(record, "\r")
+			}
+		}
+		if record != "" {
+			data := analyzer.Analyze(record)
+			summary.Records++
+			status |= outputStatus(data, failOn)
+			if data.PunycodeError != "" {
+				summary.PunycodeErrors++
+			}
+			if len(data.InvalidUTF8) > 0 {
+				summary.InvalidUTF8++
+			}
+			// with --puny the checks run over the decoded string, as they
+			// do in the analysis
+			failed := map[string]bool{}
+			for _, f := range inspect.RunChecks(cmp.Or(data.UTF8, record), summaryChecks) {
+				failed[f.Check] = true
+			}
+			if failed["mixed-script"] {
+				summary.MixedScript++
+			}
+			if failed["confusable"] {
+				summary.Confusable++
+			}
+			if failed["bidi"] {
+				summary.Bidi++
+			}
+			if len(data.Watchlist) > 0 {
+				summary.Watchlist++
+			}
+			if !policyPassed(data.Policy) {
+				summary.Policy++
+			}
+
+			if jsonOut {
+				if err := enc.Encode(data); err != nil {
+					return exitError, err
+				}
+			} else {
+				fmt.Fprintf(w, "record %d\n%s\n", summary.Records, formatPlainText(data, showRanges, table, ambiguousWide))
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if jsonOut {
+		return status, enc.Encode(struct {
+			Summary BatchSummary `json:"summary"`
+		}{summary})
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "records:\t%d\n", summary.Records)
+	fmt.Fprintf(tw, "punycode errors:\t%d\n", summary.PunycodeErrors)
+	fmt.Fprintf(tw, "invalid utf-8:\t%d\n", summary.InvalidUTF8)
+	fmt.Fprintf(tw, "mixed script:\t%d\n", summary.MixedScript)
+	fmt.Fprintf(tw, "confusable:\t%d\n", summary.Confusable)
+	fmt.Fprintf(tw, "bidi controls:\t%d\n", summary.Bidi)
+	if wl != nil {
+		fmt.Fprintf(tw, "watchlist matches:\t%d\n", summary.Watchlist)
+	}
+	if pol != nil {
+		fmt.Fprintf(tw, "policy failures:\t%d\n", summary.Policy)
+	}
+	return status, tw.Flush()
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eliheady/wtutf/inspect"
+)
+
+func TestRunBatchJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		null      bool
+		input     string
+		wantInput []string
+		want      BatchSummary
+	}{
+		{
+			name:      "newline delimited",
+			input:     "piñata\nwww.ցooցlе.com\n\npin\u0303ata\n",
+			wantInput: []string{"piñata", "www.ցooցlе.com", "pin\u0303ata"},
+			want:      BatchSummary{Records: 3, MixedScript: 1, Confusable: 1},
+		},
+		{
+			name:      "CRLF line endings",
+			input:     "piñata\r\nwww.ցooցlе.com\r\n",
+			wantInput: []string{"piñata", "www.ցooցlе.com"},
+			want:      BatchSummary{Records: 2, MixedScript: 1, Confusable: 1},
+		},
+		{
+			name:      "NUL delimited keeps carriage returns",
+			null:      true,
+			input:     "a\r\x00b\r\n",
+			wantInput: []string{"a\r", "b\r\n"},
+			want:      BatchSummary{Records: 2, MixedScript: 2},
+		},
+		{
+			name:      "NUL delimited keeps newlines",
+			null:      true,
+			input:     "a\nb\x00xn--piata-abc",
+			wantInput: []string{"a\nb", "xn--piata-abc"},
+			want:      BatchSummary{Records: 2, PunycodeErrors: 1, MixedScript: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newTestCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			if err := cmd.Flags().Set("json", "true"); err != nil {
+				t.Fatal(err)
+			}
+			if tc.null {
+				if err := cmd.Flags().Set("null", "true"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := runBatch(cmd, strings.NewReader(tc.input), nil, nil); err != nil {
+				t.Fatalf("runBatch() error = %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if len(lines) != len(tc.wantInput)+1 {
+				t.Fatalf("got %d lines, want %d records and a summary:\n%s", len(lines), len(tc.wantInput), out.String())
+			}
+			for i, want := range tc.wantInput {
+				var data inspect.OutputData
+				if err := json.Unmarshal([]byte(lines[i]), &data); err != nil {
+					t.Fatalf("line %d is not valid JSON: %v\n%s", i, err, lines[i])
+				}
+				if data.Input != want {
+					t.Errorf("line %d input = %q, want %q", i, data.Input, want)
+				}
+			}
+			var summary struct {
+				Summary BatchSummary `json:"summary"`
+			}
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+				t.Fatalf("summary line is not valid JSON: %v", err)
+			}
+			if summary.Summary != tc.want {
+				t.Errorf("summary = %+v, want %+v", summary.Summary, tc.want)
+			}
+			// without a watchlist or policy their counts are left out
+			for _, key := range []string{`"watchlist"`, `"policy"`} {
+				if strings.Contains(lines[len(lines)-1], key) {
+					t.Errorf("summary line has %s: %s", key, lines[len(lines)-1])
+				}
+			}
+		})
+	}
+}
+
+func TestRunBatchText(t *testing.T) {
+	cmd := newTestCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	if _, err := runBatch(cmd, strings.NewReader("café\nxn--piata-pta\n"), nil, nil); err != nil {
+		t.Fatalf("runBatch() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "record 1\n") || !strings.Contains(out.String(), "\nrecord 2\n") {
+		t.Errorf("expected a heading for each record:\n%s", out.String())
+	}
+	summary := `
+records:          2
+punycode errors:  0
+invalid utf-8:    0
+mixed script:     0
+confusable:       0
+bidi controls:    0
+`
+	if !strings.HasSuffix(out.String(), "\n"+summary) {
+		t.Errorf("output does not end with the summary:%s\ngot:\n%s", summary, out.String())
+	}
+}
+
+func TestRunBatchPolicy(t *testing.T) {
+	cmd := newTestCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	pol := &inspect.Policy{Scripts: []string{"Latin"}}
+	if _, err := runBatch(cmd, strings.NewReader("paypal\npаypal\nδ\n"), nil, pol); err != nil {
+		t.Fatalf("runBatch() error = %v", err)
+	}
+	summary := `
+records:          3
+punycode errors:  0
+invalid utf-8:    0
+mixed script:     1
+confusable:       1
+bidi controls:    0
+policy failures:  2
+`
+	if !strings.HasSuffix(out.String(), "\n"+summary) {
+		t.Errorf("output does not end with the summary:%s\ngot:\n%s", summary, out.String())
+	}
+}
+
+func TestRunBatchPunycode(t *testing.T) {
+	cmd := newTestCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.Flags().Set("puny", "true")
+	cmd.Flags().Set("json", "true")
+
+	wl, err := inspect.ParseWatchlist(strings.NewReader("paypal\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runBatch(cmd, strings.NewReader("xn--pypal-4ve\n"), wl, nil); err != nil {
+		t.Fatalf("runBatch() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	var summary struct {
+		Summary BatchSummary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &summary); err != nil {
+		t.Fatalf("summary line is not valid JSON: %v", err)
+	}
+	// the decoded pаypal is checked, not the A-label
+	want := BatchSummary{Records: 1, MixedScript: 1, Confusable: 1, Watchlist: 1}
+	if summary.Summary != want {
+		t.Errorf("summary = %+v, want %+v", summary.Summary, want)
+	}
+}
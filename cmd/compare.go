@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/eliheady/wtutf/inspect"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <string> <string>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Show how two similar-looking strings differ",
+	Long:  `Aligns two strings rune by rune and grapheme by grapheme, marks the code points that were inserted, removed or substituted, and reports whether the strings are equal after normalization, case folding, or UTS #39 confusable skeleton mapping. Either string, but not both, can be - to read it from --file or stdin, for strings that are awkward to pass through a shell.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, b, err := readCompareInputs(cmd, args)
+		if err != nil {
+			return err
+		}
+		data := inspect.Compare(a, b)
+		if jsonOut, _ := cmd.Flags().GetBool("json"); jsonOut {
+			b, err := json.MarshalIndent(data, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			return nil
+		}
+		fmt.Fprint(cmd.OutOrStdout(), formatCompareText(data))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+}
+
+// diffMarkers are the symbols used for each inspect.DiffOp in plain text output
+var diffMarkers = map[string]string{
+	"equal":      "=",
+	"substitute": "~",
+	"delete":     "-",
+	"insert":     "+",
+}
+
+// formatCompareText renders inspect.CompareData as plain text
+func formatCompareText(data inspect.CompareData) string {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "identical:\t%t\n", data.Identical)
+	for _, e := range data.Equivalence {
+		fmt.Fprintf(tw, "equal after %s:\t%t\n", e.Method, e.Equal)
+	}
+
+	sections := []struct {
+		name string
+		ops  []inspect.DiffOp
+	}{
+		{"runes", data.Runes},
+		{"graphemes", data.Graphemes},
+	}
+	for _, sec := range sections {
+		fmt.Fprintf(tw, "----------------------------------\n")
+		fmt.Fprintf(tw, "%s\t\ta\tb\n", sec.name)
+		for _, op := range sec.ops {
+			fmt.Fprintf(tw, "\t%s\t%s\t%s\n", diffMarkers[op.Op], describeUnit(op.A, op.ACodePoint), describeUnit(op.B, op.BCodePoint))
+		}
+	}
+	tw.Flush()
+	return b.String()
+}
+
+// describeUnit renders a rune or grapheme cluster as its code points followed
+// by a terminal-safe printable form
+func describeUnit(s string, codePoints []string) string {
+	if s == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(codePoints, " "), inspect.PoliteString(s))
+}
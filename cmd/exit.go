@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eliheady/wtutf/inspect"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses. Each kind of finding has its own bit, so a string with both
+// mixed scripts and an unterminated bidi control exits with 8|32 = 40.
+const (
+	exitOK          = 0
+	exitError       = 1 // bad flags or input that could not be read
+	exitInvalidUTF8 = 2
+	exitIDNA        = 4
+	exitMixedScript = 8
+	exitConfusable  = 16 // confusable with ASCII or with a watchlist name
+	exitBidi        = 32
+	exitOther       = 64 // invisible characters, policy rules and other checks
+)
+
+// exitStatus is the status Execute exits with when the command succeeds
+var exitStatus = exitOK
+
+// checkExitCodes maps check names to their exit status bits. Checks that are
+// not listed exit with exitOther.
+var checkExitCodes = map[string]int{
+	"utf8":         exitInvalidUTF8,
+	"idna":         exitIDNA,
+	"idna-rune":    exitIDNA,
+	"mixed-script": exitMixedScript,
+	"confusable":   exitConfusable,
+	"watchlist":    exitConfusable,
+	"bidi":         exitBidi,
+}
+
+func checkExitCode(check string) int {
+	if code, ok := checkExitCodes[check]; ok {
+		return code
+	}
+	return exitOther
+}
+
+// failOnNames are the values --fail-on accepts, from the lowest threshold to
+// the highest. "none" never fails on a finding.
+var failOnNames = []string{"note", "warning", "error", "none"}
+
+// failOnFlag returns the least severity that --fail-on counts towards the exit
+// status. For "none" it is above every severity.
+func failOnFlag(cmd *cobra.Command) (inspect.Severity, error) {
+	name, _ := cmd.Flags().GetString("fail-on")
+	if name == "none" {
+		return inspect.SeverityError + 1, nil
+	}
+	severity, err := inspect.ParseSeverity(name)
+	if err != nil {
+		return 0, fmt.Errorf("unknown --fail-on %q, want one of: %s", name, strings.Join(failOnNames, ", "))
+	}
+	return severity, nil
+}
+
+// outputStatus returns the exit status for the analysis of one string.
+// Invalid UTF-8 and a failed punycode conversion are always found, and count
+// as errors; other findings and policy rules only count when they were run.
+func outputStatus(data inspect.OutputData, failOn inspect.Severity) (status int) {
+	if inspect.SeverityError >= failOn {
+		if len(data.InvalidUTF8) > 0 {
+			status |= exitInvalidUTF8
+		}
+		if data.PunycodeError != "" {
+			status |= exitIDNA
+		}
+	}
+	findings := data.Findings
+	if data.Policy != nil {
+		for _, rule := range data.Policy.Rules {
+			findings = append(findings, rule.Findings...)
+		}
+	}
+	for _, f := range findings {
+		if f.Severity >= failOn {
+			status |= checkExitCode(f.Check)
+		}
+	}
+	return
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/eliheady/wtutf/inspect"
+)
+
+func TestOutputStatus(t *testing.T) {
+	invisible, _ := inspect.LookupCheck("invisible")
+	bidi, _ := inspect.LookupCheck("bidi")
+	checks := []inspect.Check{invisible, bidi, inspect.MixedScriptCheck(inspect.HighlyRestrictive)}
+
+	tests := []struct {
+		name   string
+		input  string
+		failOn inspect.Severity
+		want   int
+	}{
+		{"clean", "hello", inspect.SeverityNote, exitOK},
+		{"invalid UTF-8 fails conversion too", "a\xffb", inspect.SeverityNote, exitInvalidUTF8 | exitIDNA | exitMixedScript},
+		{"invisible character", "pay\u00adpal", inspect.SeverityNote, exitIDNA | exitMixedScript | exitOther},
+		{"warnings below the threshold", "pay\u00adpal", inspect.SeverityError, exitIDNA | exitMixedScript},
+		{"mixed script", "aα", inspect.SeverityNote, exitMixedScript},
+		{"bidi", "x\u2067y", inspect.SeverityWarning, exitIDNA | exitBidi | exitMixedScript},
+		{"nothing counts above error", "x\u2067y", inspect.SeverityError + 1, exitOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := inspect.NewAnalyzer(inspect.Options{Checks: checks}).Analyze(tc.input)
+			if got := outputStatus(data, tc.failOn); got != tc.want {
+				t.Errorf("outputStatus(%q) = %d, want %d", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutputStatusPolicy(t *testing.T) {
+	pol := &inspect.Policy{Scripts: []string{"Latin"}, IDNA: "strict"}
+	data := inspect.NewAnalyzer(inspect.Options{Policy: pol}).Analyze("jа_ne")
+	if got, want := outputStatus(data, inspect.SeverityNote), exitOther|exitIDNA; got != want {
+		t.Errorf("outputStatus = %d, want %d", got, want)
+	}
+}
+
+func TestFailOnFlag(t *testing.T) {
+	cmd := newTestCmd()
+	for name, want := range map[string]inspect.Severity{
+		"note":    inspect.SeverityNote,
+		"warning": inspect.SeverityWarning,
+		"error":   inspect.SeverityError,
+		"none":    inspect.SeverityError + 1,
+	} {
+		cmd.Flags().Set("fail-on", name)
+		if got, err := failOnFlag(cmd); err != nil || got != want {
+			t.Errorf("failOnFlag(%s) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	cmd.Flags().Set("fail-on", "fatal")
+	if _, err := failOnFlag(cmd); err == nil {
+		t.Error("expected an error for an unknown --fail-on")
+	}
+}
+
+func TestScanStatus(t *testing.T) {
+	data := inspect.ScanData{Findings: []inspect.ScanFinding{
+		{Check: "invisible", Severity: inspect.SeverityWarning},
+		{Check: "bidi", Severity: inspect.SeverityError},
+		{Check: "idna-rune", Severity: inspect.SeverityNote},
+	}}
+	if got, want := scanStatus(data, inspect.SeverityNote), exitOther|exitBidi|exitIDNA; got != want {
+		t.Errorf("scanStatus(note) = %d, want %d", got, want)
+	}
+	// invisible characters are warnings and idna-rune findings notes
+	if got, want := scanStatus(data, inspect.SeverityError), exitBidi; got != want {
+		t.Errorf("scanStatus(error) = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// readInput returns the string to inspect. The input comes from, in order of
+// preference: the --file flag, stdin when the argument is "-" or omitted while
+// stdin is not a terminal, or the positional argument.
+func readInput(cmd *cobra.Command, args []string) (string, error) {
+	path, _ := cmd.Flags().GetString("file")
+
+	switch {
+	case path != "" && len(args) > 0:
+		return "", errors.New("--file cannot be combined with a positional argument")
+	case path != "":
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return trimNewline(string(b)), nil
+	case len(args) > 0 && args[0] != "-":
+		return args[0], nil
+	case len(args) == 0 && isTerminal(cmd.InOrStdin()):
+		return "", errors.New("requires a string argument, --file, or input on stdin")
+	}
+
+	b, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(b)), nil
+}
+
+// readCompareInputs returns the two strings to compare. Either argument, but
+// not both, can be "-" to read that string from --file or stdin.
+func readCompareInputs(cmd *cobra.Command, args []string) (a, b string, err error) {
+	a, b = args[0], args[1]
+	switch {
+	case a == "-" && b == "-":
+		return "", "", errors.New("only one of the strings can be read from --file or stdin")
+	case a == "-":
+		a, err = readInput(cmd, nil)
+	case b == "-":
+		b, err = readInput(cmd, nil)
+	default:
+		if path, _ := cmd.Flags().GetString("file"); path != "" {
+			return "", "", errors.New(`--file needs one of the strings to be "-"`)
+		}
+	}
+	return a, b, err
+}
+
+// trimNewline removes a single trailing line feed, such as the one added by
+// echo or a text editor. Any other trailing bytes, including a carriage
+// return, are left in place so they show up in the output.
+func trimNewline(s string) string {
+	return strings.TrimSuffix(s, "\n")
+}
+
+// isTerminal reports whether r is a character device, i.e. an interactive
+// terminal rather than a pipe or a redirected file
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadInput(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+	// raw bytes that are awkward to pass through a shell: a bell, a
+	// right-to-left override and a carriage return
+	if err := os.WriteFile(path, []byte("bell\a‮\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "positional argument",
+			args: []string{"piñata"},
+			want: "piñata",
+		},
+		{
+			name:  "dash reads stdin",
+			args:  []string{"-"},
+			stdin: "piñata\n",
+			want:  "piñata",
+		},
+		{
+			name:  "no argument reads piped stdin",
+			stdin: "piñata",
+			want:  "piñata",
+		},
+		{
+			name:  "only one trailing newline is removed",
+			stdin: "a\n\n",
+			want:  "a\n",
+		},
+		{
+			name: "file",
+			file: path,
+			want: "bell\a‮\r",
+		},
+		{
+			name:    "file and argument",
+			file:    path,
+			args:    []string{"piñata"},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			file:    filepath.Join(dir, "missing.txt"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newTestCmd()
+			cmd.SetIn(strings.NewReader(tc.stdin))
+			if tc.file != "" {
+				if err := cmd.Flags().Set("file", tc.file); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := readInput(cmd, tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("readInput() = %q, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readInput() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("readInput() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReadCompareInputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("bell\a\u202e\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		stdin   string
+		wantA   string
+		wantB   string
+		wantErr bool
+	}{
+		{name: "arguments", args: []string{"piñata", "piñata"}, wantA: "piñata", wantB: "piñata"},
+		{name: "first from stdin", args: []string{"-", "b"}, stdin: "bell\a\n", wantA: "bell\a", wantB: "b"},
+		{name: "second from stdin", args: []string{"a", "-"}, stdin: "bell\a\n", wantA: "a", wantB: "bell\a"},
+		{name: "second from file", args: []string{"a", "-"}, file: path, wantA: "a", wantB: "bell\a\u202e\r"},
+		{name: "both from stdin", args: []string{"-", "-"}, stdin: "a\n", wantErr: true},
+		{name: "file without dash", args: []string{"a", "b"}, file: path, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newTestCmd()
+			cmd.SetIn(strings.NewReader(tc.stdin))
+			if tc.file != "" {
+				if err := cmd.Flags().Set("file", tc.file); err != nil {
+					t.Fatal(err)
+				}
+			}
+			a, b, err := readCompareInputs(cmd, tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("readCompareInputs() = %q, %q, want error", a, b)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCompareInputs() error = %v", err)
+			}
+			if a != tc.wantA || b != tc.wantB {
+				t.Errorf("readCompareInputs() = %q, %q, want %q, %q", a, b, tc.wantA, tc.wantB)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/eliheady/wtutf/inspect"
 	"github.com/spf13/cobra"
 )
 
@@ -13,11 +14,29 @@ func newTestCmd() *cobra.Command {
 	c := &cobra.Command{Use: "test"}
 	// register flags that parseFlags reads
 	c.Flags().BoolP("check", "c", false, "")
+	c.Flags().String("check-level", inspect.HighlyRestrictive.String(), "")
+	c.Flags().StringSlice("checks", nil, "")
+	c.Flags().String("fail-on", "note", "")
 	c.Flags().BoolP("show-ranges", "r", false, "")
 	c.Flags().BoolP("strict", "s", false, "")
+	c.Flags().Bool("transitional", false, "")
 	c.Flags().BoolP("puny", "p", false, "")
 	c.Flags().BoolP("table", "t", false, "")
+	c.Flags().BoolP("properties", "P", false, "")
+	c.Flags().BoolP("graphemes", "g", false, "")
+	c.Flags().Bool("ambiguous-wide", false, "")
+	c.Flags().BoolP("bidi", "d", false, "")
+	c.Flags().BoolP("labels", "l", false, "")
+	c.Flags().Bool("profiles", false, "")
+	c.Flags().Bool("precis", false, "")
 	c.Flags().Bool("json", false, "")
+	c.Flags().StringP("file", "f", "", "")
+	c.Flags().BoolP("normalize", "n", false, "")
+	c.Flags().BoolP("confusables", "k", false, "")
+	c.Flags().StringP("watchlist", "w", "", "")
+	c.Flags().String("policy", "", "")
+	c.Flags().BoolP("batch", "b", false, "")
+	c.Flags().BoolP("null", "0", false, "")
 	return c
 }
 
@@ -55,6 +74,18 @@ func TestParseFlagsOutputs(t *testing.T) {
 			wantJSON:    false,
 			wantContain: "utf-8:",
 		},
+		{
+			name:        "selected checks are reported",
+			setFlags:    map[string]string{"checks": "bidi,invisible"},
+			args:        []string{"a\u200bb\u202e"},
+			wantContain: "invisible (warning): byte 1: invisible character ZERO WIDTH SPACE",
+		},
+		{
+			name:     "checks in json output",
+			setFlags: map[string]string{"checks": "mixed-script", "json": "true"},
+			args:     []string{"a\u03b1"},
+			wantJSON: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -67,11 +98,11 @@ func TestParseFlagsOutputs(t *testing.T) {
 				}
 			}
 
-			out := parseFlags(cmd, tc.args)
+			out, _ := parseFlags(cmd, tc.args, nil, nil)
 
 			if tc.wantJSON {
-				// should be valid JSON and map to OutputData
-				var data OutputData
+				// should be valid JSON and map to inspect.OutputData
+				var data inspect.OutputData
 				if err := json.Unmarshal([]byte(out), &data); err != nil {
 					t.Fatalf("expected valid JSON output but got error: %v\noutput: %s", err, out)
 				}
@@ -88,3 +119,36 @@ func TestParseFlagsOutputs(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectedChecks(t *testing.T) {
+	cmd := newTestCmd()
+	if checks, err := selectedChecks(cmd, nil); err != nil || len(checks) != 0 {
+		t.Errorf("expected no checks by default, got %d, %v", len(checks), err)
+	}
+
+	cmd.Flags().Set("check", "true")
+	checks, err := selectedChecks(cmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range checks {
+		names = append(names, c.Name())
+	}
+	if got := strings.Join(names, ","); got != strings.Join(defaultChecks, ",") {
+		t.Errorf("--check runs %s, want %v", got, defaultChecks)
+	}
+
+	// the mixed-script check follows --check-level
+	cmd.Flags().Set("checks", "mixed-script")
+	cmd.Flags().Set("check-level", "minimally-restrictive")
+	checks, _ = selectedChecks(cmd, nil)
+	if findings := inspect.RunChecks("a\u03b1", checks); len(findings) != 0 {
+		t.Errorf("expected Latin+Greek to pass at minimally-restrictive, got %+v", findings)
+	}
+
+	cmd.Flags().Set("checks", "nope")
+	if _, err := selectedChecks(cmd, nil); err == nil || !strings.Contains(err.Error(), "mixed-script") {
+		t.Errorf("expected an error listing the known checks, got %v", err)
+	}
+}
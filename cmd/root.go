@@ -2,206 +2,220 @@
 package cmd
 
 import (
-	"encoding/hex"
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
-	"unicode"
-	"unicode/utf8"
 
+	"github.com/eliheady/wtutf/inspect"
 	"github.com/spf13/cobra"
-	"golang.org/x/net/idna"
 )
 
-// OutputData holds the structured output for both text and JSON formats
-type OutputData struct {
-	Input         string         `json:"input"`
-	Punycode      string         `json:"punycode,omitempty"`
-	UTF8          string         `json:"utf8,omitempty"`
-	PunycodeError string         `json:"punycode_error,omitempty"`
-	TotalBytes    int            `json:"total_bytes"`
-	Characters    int            `json:"characters"`
-	UnicodeRanges map[string]int `json:"unicode_ranges,omitempty"`
-	Table         []RuneTableRow `json:"table,omitempty"`
-}
-
-type RuneTableRow struct {
-	Printable string   `json:"printable"`
-	CodePoint string   `json:"code_point"`
-	Bytes     string   `json:"bytes"`
-	Length    int      `json:"length"`
-	Errors    []string `json:"errors,omitempty"`
-}
-
-type RuneCache struct {
-	Printable string
-	Padded    string
-	Bytes     string
-	Errors    []string
-}
-
 var rootCmd = &cobra.Command{
-	Use:   "wtutf",
-	Args:  cobra.ExactArgs(1),
+	Use:   "wtutf [string | -]",
+	Args:  cobra.MaximumNArgs(1),
 	Short: "A simple utility to reduce ASCII-centrism",
-	Long:  `This program just prints out the Unicode code points of the string you feed into it. It can also show you the punycode conversion of your string, or failure reasons if conversion isn't possible.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Print(parseFlags(cmd, args))
+	Long: `This program just prints out the Unicode code points of the string you feed into it. It can also show you the punycode conversion of your string, or failure reasons if conversion isn't possible.
+
+The string can be given as an argument, read from a file with --file, or piped in on stdin (use "-" or omit the argument). A single trailing newline is removed from file and stdin input.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := checkLevelFlag(cmd); err != nil {
+			return err
+		}
+		if _, err := failOnFlag(cmd); err != nil {
+			return err
+		}
+		var wl *inspect.Watchlist
+		if path, _ := cmd.Flags().GetString("watchlist"); path != "" {
+			var err error
+			if wl, err = inspect.LoadWatchlist(path); err != nil {
+				return err
+			}
+		}
+		var pol *inspect.Policy
+		if path, _ := cmd.Flags().GetString("policy"); path != "" {
+			var err error
+			if pol, err = inspect.LoadPolicy(path); err != nil {
+				return err
+			}
+		}
+		if _, err := selectedChecks(cmd, wl); err != nil {
+			return err
+		}
+		if batch, _ := cmd.Flags().GetBool("batch"); batch {
+			r, closeInput, err := openBatchInput(cmd, args)
+			if err != nil {
+				return err
+			}
+			defer closeInput()
+			status, err := runBatch(cmd, r, wl, pol)
+			exitStatus = status
+			return err
+		}
+		input, err := readInput(cmd, args)
+		if err != nil {
+			return err
+		}
+		out, status := parseFlags(cmd, []string{input}, wl, pol)
+		fmt.Print(out)
+		exitStatus = status
+		return nil
 	},
 }
 
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitError)
 	}
+	os.Exit(exitStatus)
 }
 
 func init() {
-	var check, showRanges, strict, fromPuny, table, jsonOut bool
-	rootCmd.PersistentFlags().BoolVarP(&check, "check", "c", false, "Check whether the string contains characters from more than one Unicode range")
+	var check, showRanges, strict, transitional, fromPuny, table, properties, graphemes, ambiguousWide, bidiCheck, labels, profiles, precisFlag, jsonOut, batch, nullDelim, normalize, confusable bool
+	var file, watchlist, policy, checkLevel, failOn string
+	var checks []string
+	rootCmd.PersistentFlags().BoolVarP(&check, "check", "c", false, "Run the --checks and print nothing but the policy results (with --show-ranges, the findings too); the exit status tells what was found")
+	rootCmd.PersistentFlags().StringVar(&failOn, "fail-on", "note", "Least severe finding that sets the exit status: "+strings.Join(failOnNames, ", "))
+	rootCmd.PersistentFlags().StringSliceVar(&checks, "checks", nil, "Comma-separated checks to run and report: "+strings.Join(inspect.CheckNames(), ", ")+" (--check defaults to "+strings.Join(defaultChecks, ",")+")")
+	rootCmd.PersistentFlags().StringVar(&checkLevel, "check-level", inspect.HighlyRestrictive.String(), "Least restrictive UTS #39 restriction level --check accepts: "+strings.Join(inspect.RestrictionLevelNames(), ", "))
 	rootCmd.PersistentFlags().BoolVarP(&showRanges, "show-ranges", "r", false, "Show the Unicode script ranges included in the string")
 	rootCmd.PersistentFlags().BoolVarP(&strict, "strict", "s", false, "Set strict punycode conversion rules")
+	rootCmd.PersistentFlags().BoolVar(&transitional, "transitional", false, "Map the input as UTS #46 lookup does with transitional (IDNA2003 compatible) processing of ß, ς, ZWJ and ZWNJ")
 	rootCmd.PersistentFlags().BoolVarP(&fromPuny, "puny", "p", false, "Convert from punycode")
 	rootCmd.PersistentFlags().BoolVarP(&table, "table", "t", false, "Show table of all included unicode characters")
+	rootCmd.PersistentFlags().BoolVarP(&properties, "properties", "P", false, "Show the Unicode name, category, block, bidi class, East Asian width and age of each character (implies --table)")
+	rootCmd.PersistentFlags().BoolVarP(&graphemes, "graphemes", "g", false, "Count user-perceived characters (UAX #29 grapheme clusters) and group the table by cluster")
+	rootCmd.PersistentFlags().BoolVar(&ambiguousWide, "ambiguous-wide", false, "Count East Asian Ambiguous width characters as two terminal cells when aligning the table, as CJK terminals do")
 	rootCmd.PersistentFlags().BoolVar(&jsonOut, "json", false, "Output results as JSON instead of plain text")
+	rootCmd.PersistentFlags().StringVarP(&file, "file", "f", "", "Read the input string from a file instead of an argument")
+	rootCmd.PersistentFlags().BoolVarP(&normalize, "normalize", "n", false, "Show the string under the NFC, NFD, NFKC and NFKD normalization forms")
+	rootCmd.PersistentFlags().BoolVarP(&confusable, "confusables", "k", false, "Show the UTS #39 confusable skeleton of the string")
+	rootCmd.PersistentFlags().StringVar(&policy, "policy", "", "YAML file of allowed scripts, blocks, categories, lengths, normalization form and forbidden code points to evaluate the string against (--check then runs only the policy, unless --checks is given)")
+	rootCmd.PersistentFlags().StringVarP(&watchlist, "watchlist", "w", "", "File of protected names, one per line, to check the string for confusable look-alikes of")
+	rootCmd.PersistentFlags().BoolVarP(&labels, "labels", "l", false, "Convert each label of a domain name separately and show which labels fail which rules")
+	rootCmd.PersistentFlags().BoolVar(&profiles, "profiles", false, "Compare the conversion under the idna package's Punycode, Lookup, Display and Registration profiles and wtutf's own rules")
+	rootCmd.PersistentFlags().BoolVar(&precisFlag, "precis", false, "Enforce the PRECIS UsernameCaseMapped, UsernameCasePreserved, OpaqueString and Nickname profiles (RFC 8264, 8265, 8266) and show the enforced string or the code points each rejects")
+	rootCmd.PersistentFlags().BoolVarP(&bidiCheck, "bidi", "d", false, "Report unterminated or unmatched bidi embeddings, overrides and isolates (Trojan Source)")
+	rootCmd.PersistentFlags().BoolVarP(&batch, "batch", "b", false, "Analyze each line of the input separately (NDJSON with --json)")
+	rootCmd.PersistentFlags().BoolVarP(&nullDelim, "null", "0", false, "Batch records are NUL-delimited instead of newline-delimited")
 }
 
-func parseFlags(cmd *cobra.Command, args []string) string {
+// parseFlags analyzes args[0] as the flags ask and returns the output and the
+// exit status
+func parseFlags(cmd *cobra.Command, args []string, wl *inspect.Watchlist, pol *inspect.Policy) (string, int) {
 	flags := cmd.Flags()
+	failOn, _ := failOnFlag(cmd)
 
 	showRanges, _ := flags.GetBool("show-ranges")
-	strict, _ := flags.GetBool("strict")
-	punyDecode, _ := flags.GetBool("puny")
 	table, _ := flags.GetBool("table")
+	properties, _ := flags.GetBool("properties")
+	table = table || properties
 	jsonOut, _ := flags.GetBool("json")
 
-	if compare, _ := flags.GetBool("check"); compare {
-		var checkResult int
-		if checkMultipleRange(os.Stdout, showRanges, args[0]) {
-			checkResult = 1
+	data := newAnalyzer(cmd, wl, pol).Analyze(args[0])
+	status := outputStatus(data, failOn)
+	if check, _ := flags.GetBool("check"); check {
+		var b strings.Builder
+		if data.Policy != nil {
+			tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+			policySummary(tw, data.Policy)
+			tw.Flush()
 		}
-		os.Exit(checkResult)
+		if showRanges {
+			for _, f := range data.Findings {
+				fmt.Fprintln(&b, describeFinding(f))
+			}
+		}
+		return b.String(), status
 	}
 
-	data := gatherOutputData(args[0], showRanges, strict, punyDecode, table)
 	if jsonOut {
 		b, err := json.MarshalIndent(data, "", "  ")
 		if err != nil {
-			return "Error encoding JSON: " + err.Error()
+			return "Error encoding JSON: " + err.Error(), exitError
 		}
-		return string(b) + "\n"
+		return string(b) + "\n", status
 	}
-	return formatPlainText(data, showRanges, table)
+	ambiguousWide, _ := flags.GetBool("ambiguous-wide")
+	return formatPlainText(data, showRanges, table, ambiguousWide), status
 }
 
-// toString takes a rune returns a string with padding appropriate for the character width
-func toPaddedString(r rune, defaultPad int) (out string) {
-	defaultPattern := fmt.Sprintf("%% %ds", defaultPad)
-	widePadLeft := fmt.Sprintf("%% %ds", defaultPad-2)
-	widePattern := fmt.Sprintf("%s%%-1s", widePadLeft)
-	// Unicode blocks containing 'wide' or combining characters
-	fullwidth := 0xFF00 <= r && r <= 0xFFEF
-	symbols := unicode.Is(unicode.Symbol, r)
-	wide := unicode.Is(unicode.Ideographic, r) || unicode.Is(unicode.Extender, r)
-	wide = wide || unicode.Is(unicode.Diacritic, r) || unicode.Is(unicode.Hangul, r)
-	wide = wide || unicode.Is(unicode.Bopomofo, r) || unicode.Is(unicode.Han, r)
-	wide = wide || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
-	wide = wide || fullwidth || symbols
-	switch {
-	case wide:
-		out = fmt.Sprintf(widePattern, " ", politePrint(r))
-	default:
-		out = fmt.Sprintf(defaultPattern, politePrint(r))
+// newAnalyzer returns an inspect.Analyzer for the options selected on the command
+// line. The watchlist and policy may be nil.
+func newAnalyzer(cmd *cobra.Command, wl *inspect.Watchlist, pol *inspect.Policy) *inspect.Analyzer {
+	flags := cmd.Flags()
+	opts := inspect.Options{Watchlist: wl, Policy: pol}
+	for name, opt := range map[string]*bool{
+		"strict":       &opts.Strict,
+		"transitional": &opts.Transitional,
+		"puny":         &opts.FromPunycode,
+		"show-ranges":  &opts.Ranges,
+		"table":        &opts.Table,
+		"properties":   &opts.Properties,
+		"graphemes":    &opts.Graphemes,
+		"normalize":    &opts.Normalization,
+		"confusables":  &opts.Confusables,
+		"labels":       &opts.Labels,
+		"profiles":     &opts.Profiles,
+		"precis":       &opts.PRECIS,
+		"bidi":         &opts.Bidi,
+	} {
+		*opt, _ = flags.GetBool(name)
 	}
-	return
+	opts.Checks, _ = selectedChecks(cmd, wl)
+	return inspect.NewAnalyzer(opts)
 }
 
-// gatherOutputData collects all output data for a given input string
-func gatherOutputData(ustring string, showRanges, strict, punyDecode, table bool) OutputData {
-	rules := []idna.Option{
-		idna.BidiRule(),
-		idna.CheckJoiners(true),
-		idna.ValidateLabels(true),
-	}
-	if strict {
-		rules = append(rules,
-			idna.ValidateForRegistration(),
-			idna.StrictDomainName(true),
-		)
-	}
+// defaultChecks are the checks --check runs when neither --checks nor
+// --policy is given
+var defaultChecks = []string{"mixed-script", "confusable", "bidi", "watchlist"}
 
-	data := OutputData{
-		Input:      ustring,
-		TotalBytes: len(ustring),
-		Characters: utf8.RuneCountInString(ustring),
+// selectedChecks returns the checks named with --checks. The checks that
+// take options are set up from --check-level, --strict, --transitional and
+// the watchlist, which may be nil.
+func selectedChecks(cmd *cobra.Command, wl *inspect.Watchlist) ([]inspect.Check, error) {
+	flags := cmd.Flags()
+	names, _ := flags.GetStringSlice("checks")
+	policy, _ := flags.GetString("policy")
+	if check, _ := flags.GetBool("check"); check && len(names) == 0 && policy == "" {
+		names = defaultChecks
 	}
-
-	var punyConverted bool
-
-	if punyDecode {
-		if utfString, err := fromPuny(ustring, rules); err == nil {
-			data.Punycode = ustring
-			data.UTF8 = utfString
-			ustring = utfString
-		} else {
-			data.PunycodeError = "could not decode punycode input"
-		}
-	} else {
-		if punycode, err := toPuny(ustring, rules); err == nil {
-			punyConverted = true
-			data.Punycode = punycode
-		} else {
-			data.PunycodeError = "could not punycode-convert input"
-		}
+	accept, err := checkLevelFlag(cmd)
+	if err != nil {
+		return nil, err
 	}
-
-	if showRanges {
-		data.UnicodeRanges = listRanges(ustring)
+	strict, _ := flags.GetBool("strict")
+	transitional, _ := flags.GetBool("transitional")
+	configured := map[string]inspect.Check{
+		"idna":         inspect.IDNACheck(strict, transitional),
+		"mixed-script": inspect.MixedScriptCheck(accept),
+		"watchlist":    inspect.WatchlistCheck(wl),
 	}
 
-	if table {
-		runeCache := map[rune]RuneCache{}
-		for _, r := range ustring {
-			var printable, padded, runeBytes string
-			var runeErrors []string
-			if rc, ok := runeCache[r]; ok {
-				printable = rc.Printable
-				padded = rc.Padded
-				runeBytes = rc.Bytes
-				runeErrors = rc.Errors
-			} else {
-				printable = toPaddedString(r, 3)
-				padded = fmt.Sprintf("%#0*x", (utf8.RuneLen(r) * 2), r)
-				runeBytes = hex.EncodeToString([]byte(string(r)))
-				if !punyConverted {
-					runeErrors = enumerateErrors(r)
-				}
-				runeCache[r] = RuneCache{
-					Printable: printable,
-					Padded:    padded,
-					Bytes:     runeBytes,
-					Errors:    runeErrors,
-				}
-			}
-			row := RuneTableRow{
-				Printable: printable,
-				CodePoint: padded,
-				Bytes:     runeBytes,
-				Length:    utf8.RuneLen(r),
-				Errors:    runeErrors,
+	var checks []inspect.Check
+	for _, name := range names {
+		c, ok := configured[name]
+		if !ok {
+			if c, ok = inspect.LookupCheck(name); !ok {
+				return nil, fmt.Errorf("unknown check %q, want one of: %s", name, strings.Join(inspect.CheckNames(), ", "))
 			}
-			data.Table = append(data.Table, row)
 		}
+		checks = append(checks, c)
 	}
+	return checks, nil
+}
 
-	return data
+// checkLevelFlag returns the restriction level selected with --check-level
+func checkLevelFlag(cmd *cobra.Command) (inspect.RestrictionLevel, error) {
+	name, _ := cmd.Flags().GetString("check-level")
+	return inspect.ParseRestrictionLevel(name)
 }
 
-// formatPlainText renders OutputData as a plain text table output
-func formatPlainText(data OutputData, showRanges, table bool) string {
+// formatPlainText renders inspect.OutputData as a plain text table output
+func formatPlainText(data inspect.OutputData, showRanges, table, ambiguousWide bool) string {
 	var b strings.Builder
 	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
 
@@ -213,23 +227,175 @@ func formatPlainText(data OutputData, showRanges, table bool) string {
 		fmt.Fprintf(tw, "punycode:\t%s\n", data.Punycode)
 	} else if data.PunycodeError != "" {
 		fmt.Fprintf(tw, "%s\n", data.PunycodeError)
+		if e := data.IDNAError; e != nil {
+			idnaErrorSummary(tw, e)
+		}
+	}
+	if rt := data.RoundTrip; rt != nil {
+		if rt.Converted != "" && !rt.Stable {
+			fmt.Fprintf(tw, "round trip:\t%s\n", describeRoundTrip(data.Input, rt))
+		}
+		for _, fake := range rt.FakeALabels {
+			fmt.Fprintf(tw, "fake a-label:\tbyte %d: %s %s\n", fake.Offset, inspect.PoliteString(fake.Label), fake.Reason)
+		}
+	}
+	if d := data.Deviations; d != nil {
+		for i, c := range d.Characters {
+			label := ""
+			if i == 0 {
+				label = "deviations:"
+			}
+			fmt.Fprintf(tw, "%s\t%s\n", label, describeDeviation(c))
+		}
+		if d.Differ {
+			fmt.Fprintf(tw, "transitional:\t%s\n", cmp.Or(d.Transitional, d.TransitionalError))
+			fmt.Fprintf(tw, "nontransitional:\t%s\n", cmp.Or(d.NonTransitional, d.NonTransitionalError))
+		}
+	}
+	for i, l := range data.Labels {
+		fmt.Fprintf(tw, "label %d:\t%s (%d bytes)\n", i+1, labelSummary(l), l.Length)
+		if l.Error != "" {
+			fmt.Fprintf(tw, "\t  %s [%s]\n", l.Error, l.ErrorCode)
+		}
+		if len(l.FailedRules) > 0 {
+			fmt.Fprintf(tw, "\t  fails %s\n", strings.Join(l.FailedRules, ", "))
+		}
 	}
 	fmt.Fprintf(tw, "total bytes:\t%d\n", data.TotalBytes)
 	fmt.Fprintf(tw, "characters:\t%d\n", data.Characters)
+	if data.Graphemes != nil {
+		fmt.Fprintf(tw, "graphemes:\t%d\n", data.GraphemeCount)
+		for _, gc := range data.Graphemes {
+			if marks := gc.Marks(); marks != "" {
+				fmt.Fprintf(tw, "\tbyte %d: %s %s\n", gc.Offset, strings.Join(gc.CodePoints, " "), marks)
+			}
+		}
+	}
+	for i, seq := range data.InvalidUTF8 {
+		label := ""
+		if i == 0 {
+			label = "invalid utf-8:"
+		}
+		fmt.Fprintf(tw, "%s\tbyte %d: %s (%s)\n", label, seq.Offset, seq.Bytes, seq.Reason)
+	}
+
+	if data.Normalization != nil {
+		fmt.Fprintf(tw, "normal forms:\t%s\n", normalFormSummary(data.Normalization))
+		for _, f := range data.Normalization {
+			if f.IsNormal {
+				fmt.Fprintf(tw, "%s:\tunchanged\n", f.Form)
+				continue
+			}
+			fmt.Fprintf(tw, "%s:\t%d change(s)\n", f.Form, len(f.Changes))
+			for _, c := range f.Changes {
+				fmt.Fprintf(tw, "\tbyte %d: %s -> %s\n", c.Offset, strings.Join(c.From, " "), strings.Join(c.To, " "))
+			}
+		}
+	}
+
+	if data.Confusables != nil {
+		fmt.Fprintf(tw, "skeleton:\t%s\n", inspect.PoliteString(data.Confusables.Skeleton))
+		if data.Confusables.ASCIILike {
+			fmt.Fprintf(tw, "confusable:\tlooks like the ASCII string above\n")
+		}
+		for _, m := range data.Confusables.Mappings {
+			fmt.Fprintf(tw, "\tbyte %d: %s -> %s\n", m.Offset, m.CodePoint, strings.Join(m.Prototype, " "))
+		}
+	}
+
+	for i, m := range data.Watchlist {
+		label := ""
+		if i == 0 {
+			label = "watchlist:"
+		}
+		fmt.Fprintf(tw, "%s\tbyte %d: %s is confusable with %s\n", label, m.Offset, inspect.PoliteString(m.Matched), inspect.PoliteString(m.Name))
+		for _, op := range m.Swaps {
+			fmt.Fprintf(tw, "\t  %s\n", inspect.DescribeSwap(op))
+		}
+	}
+
+	if data.Bidi != nil {
+		fmt.Fprintf(tw, "bidi controls:\t%d\n", data.Bidi.Controls)
+		for _, issue := range data.Bidi.Issues {
+			fmt.Fprintf(tw, "\tbyte %d: %s %s %s\n", issue.Offset, issue.CodePoint, issue.Name, issue.Problem)
+		}
+	}
+
+	for i, f := range data.Findings {
+		label := ""
+		if i == 0 {
+			label = "findings:"
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", label, describeFinding(f))
+	}
+
+	if data.Policy != nil {
+		policySummary(tw, data.Policy)
+	}
 
 	if showRanges && data.UnicodeRanges != nil {
 		fmt.Fprintf(tw, "unicode ranges:\n")
 		for i, count := range data.UnicodeRanges {
 			fmt.Fprintf(tw, "\t%s:	%d\n", i, count)
 		}
+		fmt.Fprintf(tw, "restriction level:\t%s\n", data.Restriction)
+	}
+
+	if data.Profiles != nil {
+		fmt.Fprintf(tw, "idna profiles:\n")
+		rows := [][]string{{"  profile", "a-label", "u-label", "error"}}
+		for _, p := range data.Profiles {
+			var errorColumn string
+			if p.Error != "" {
+				errorColumn = fmt.Sprintf("%s [%s]", p.Error, p.ErrorCode)
+			}
+			rows = append(rows, []string{"  " + p.Profile, p.ALabel, inspect.PoliteString(p.ULabel), errorColumn})
+		}
+		tw.Flush()
+		writeTable(&b, rows, ambiguousWide)
+	}
+
+	if data.PRECIS != nil {
+		fmt.Fprintf(tw, "precis profiles:\n")
+		rows := [][]string{{"  profile", "output", "error"}}
+		for _, p := range data.PRECIS {
+			var errorColumn string
+			if p.Error != "" {
+				errorColumn = fmt.Sprintf("%s [%s]", p.Error, p.ErrorCode)
+			}
+			for _, r := range p.Runes {
+				errorColumn += fmt.Sprintf(", byte %d: %s %s (%s)", r.Offset, r.CodePoint, r.Name, r.Rule)
+			}
+			rows = append(rows, []string{"  " + p.Profile, inspect.PoliteString(p.Output), errorColumn})
+		}
+		tw.Flush()
+		writeTable(&b, rows, ambiguousWide)
 	}
 
 	if table && len(data.Table) > 0 {
 		fmt.Fprintf(tw, "----------------------------------\n")
-		header := []string{"printable", "code point", "bytes (len)"}
+		header := []string{"printable", "code point", "bytes (len)", "scripts"}
+		// in grapheme mode, rows are grouped under the cluster they belong to
+		clusterStarts := map[int]string{}
+		for i, gc := range data.Graphemes {
+			clusterStarts[gc.Offset] = strings.TrimSpace(fmt.Sprintf("%d %s", i+1, gc.Marks()))
+		}
+		if data.Graphemes != nil {
+			header = append([]string{"cluster"}, header...)
+		}
+		hasProperties := false
+		for _, row := range data.Table {
+			if row.RuneProperties != nil {
+				hasProperties = true
+				break
+			}
+		}
+		if hasProperties {
+			header = append(header, "name", "category", "block", "bidi", "width", "age")
+		}
 		hasErrors := false
 		for _, row := range data.Table {
-			if len(row.Errors) > 0 {
+			if len(row.Errors) > 0 || row.Invalid != "" || len(row.Watchlist) > 0 || len(row.Findings) > 0 || len(row.PRECIS) > 0 {
 				hasErrors = true
 				break
 			}
@@ -237,20 +403,55 @@ func formatPlainText(data OutputData, showRanges, table bool) string {
 		if hasErrors {
 			header = append(header, "conversion rules violated")
 		}
-		fmt.Fprintf(tw, "%s\t%s\t%s", header[0], header[1], header[2])
-		if len(header) > 3 {
-			fmt.Fprintf(tw, "\t%s", header[3])
-		}
-		fmt.Fprintf(tw, "\n")
+		// the table is aligned by terminal cells rather than with the
+		// tabwriter, which counts wide and combining characters as one column
+		rows := [][]string{header}
 		for _, row := range data.Table {
 			bytesColumn := fmt.Sprintf("%s (%d)", row.Bytes, row.Length)
 			errors := strings.Join(row.Errors, ", ")
-			fmt.Fprintf(tw, "%s\t%s\t%s", row.Printable, row.CodePoint, bytesColumn)
+			if row.Invalid != "" {
+				errors = "invalid UTF-8: " + row.Invalid
+			}
+			if len(row.Watchlist) > 0 {
+				notes := "watchlist " + strings.Join(row.Watchlist, ", ")
+				if errors != "" {
+					notes = errors + ", " + notes
+				}
+				errors = notes
+			}
+			if len(row.Findings) > 0 {
+				notes := strings.Join(row.Findings, ", ")
+				if errors != "" {
+					notes = errors + ", " + notes
+				}
+				errors = notes
+			}
+			if len(row.PRECIS) > 0 {
+				notes := "precis " + strings.Join(row.PRECIS, ", ")
+				if errors != "" {
+					notes = errors + ", " + notes
+				}
+				errors = notes
+			}
+			var cells []string
+			if data.Graphemes != nil {
+				cells = append(cells, clusterStarts[row.Offset])
+			}
+			cells = append(cells, padLeft(row.Printable, 3, ambiguousWide), row.CodePoint, bytesColumn, strings.Join(row.Scripts, " "))
+			if hasProperties {
+				props := row.RuneProperties
+				if props == nil {
+					props = &inspect.RuneProperties{}
+				}
+				cells = append(cells, props.Name, props.Category, props.Block, props.BidiClass, props.EastAsianWidth, props.Age)
+			}
 			if hasErrors {
-				fmt.Fprintf(tw, "\t%s", errors)
+				cells = append(cells, errors)
 			}
-			fmt.Fprintf(tw, "\n")
+			rows = append(rows, cells)
 		}
+		tw.Flush()
+		writeTable(&b, rows, ambiguousWide)
 	}
 	tw.Flush()
 	return b.String()
@@ -3,12 +3,14 @@ package cmd
 import (
 	"strings"
 	"testing"
+
+	"github.com/eliheady/wtutf/inspect"
 )
 
 func TestTableOutput(t *testing.T) {
 	input := "café"
-	data := gatherOutputData(input, false, false, false, true)
-	outStr := formatPlainText(data, false, true)
+	data := inspect.NewAnalyzer(inspect.Options{Table: true}).Analyze(input)
+	outStr := formatPlainText(data, false, true, false)
 
 	if !strings.Contains(outStr, "code point") || !strings.Contains(outStr, "bytes (len)") {
 		t.Errorf("table header missing in output: %s", outStr)
@@ -18,112 +20,82 @@ func TestTableOutput(t *testing.T) {
 	}
 }
 
-func TestJSONOutput(t *testing.T) {
-	tests := []struct {
-		name       string
-		input      string
-		showRanges bool
-		wantRunes  []string
-	}{
-		{
-			name:       "basic table",
-			input:      "café",
-			showRanges: false,
-			wantRunes:  []string{"c", "a", "f", "é"},
-		},
-		{
-			name:       "table with unicode ranges",
-			input:      "café",
-			showRanges: true,
-			wantRunes:  []string{"c", "a", "f", "é"},
-		},
+func TestGraphemeTable(t *testing.T) {
+	cmd := newTestCmd()
+	cmd.Flags().Set("graphemes", "true")
+	cmd.Flags().Set("table", "true")
+	out, _ := parseFlags(cmd, []string{"ké"}, nil, nil)
+
+	if !strings.Contains(out, "characters:   3\ngraphemes:    2\n") {
+		t.Errorf("expected rune and grapheme counts, got:\n%s", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	rows := lines[len(lines)-3:]
+	for i, wantCluster := range []string{"1", "2", ""} {
+		if got := strings.Fields(rows[i])[0]; wantCluster != "" && got != wantCluster {
+			t.Errorf("row %d: expected cluster %q, got line %q", i, wantCluster, rows[i])
+		}
+	}
+	if !strings.HasPrefix(rows[2], " ") {
+		t.Errorf("expected the combining mark to continue cluster 2, got line %q", rows[2])
+	}
+}
+
+func TestFormatPlainTextProfiles(t *testing.T) {
+	data := inspect.OutputData{Profiles: inspect.Profiles("a_b.example")}
+	out := formatPlainText(data, false, false, false)
+	for _, want := range []string{"idna profiles:", "  Lookup ", "[std3]", "  wtutf --strict "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatPlainTextPRECIS(t *testing.T) {
+	data := inspect.NewAnalyzer(inspect.Options{Table: true, PRECIS: true}).Analyze("Jane Doe")
+	out := formatPlainText(data, false, true, false)
+	for _, want := range []string{"precis profiles:", "  Nickname               Jane Doe", "byte 4: U+0020 SPACE (Spaces)", "precis UsernameCaseMapped: Spaces, UsernameCasePreserved: Spaces"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatPlainTextRoundTrip(t *testing.T) {
+	data := inspect.NewAnalyzer(inspect.Options{Transitional: true}).Analyze("faß.xn--ls8h")
+	out := formatPlainText(data, false, false, false)
+	for _, want := range []string{"round trip:", "(unstable)", "fake a-label:", "byte 5: xn--ls8h decodes to"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
 	}
+}
 
-	for _, tc := range tests {
-		t.Run(tc.name, func(t *testing.T) {
-			data := gatherOutputData(tc.input, tc.showRanges, false, false, true)
-			if data.Input != tc.input {
-				t.Errorf("expected input %q, got %q", tc.input, data.Input)
-			}
-			if len(data.Table) == 0 {
-				t.Errorf("expected non-empty Table, got empty")
-			}
-			for _, wantRune := range tc.wantRunes {
-				found := false
-				for _, row := range data.Table {
-					if strings.TrimSpace(row.Printable) == wantRune {
-						found = true
-						break
-					}
-				}
-				if !found {
-					t.Errorf("expected rune %q not found in Table: %+v", wantRune, data.Table)
-				}
-			}
-			if tc.showRanges && len(data.UnicodeRanges) == 0 {
-				t.Errorf("expected UnicodeRanges to be populated when showRanges is true")
-			}
-			if !tc.showRanges && len(data.UnicodeRanges) != 0 {
-				t.Errorf("expected UnicodeRanges to be empty when showRanges is false")
-			}
-		})
+func TestFormatPlainTextDeviations(t *testing.T) {
+	data := inspect.NewAnalyzer(inspect.Options{Transitional: true}).Analyze("Faß.de")
+	out := formatPlainText(data, false, false, false)
+	for _, want := range []string{"U+00DF LATIN SMALL LETTER SHARP S -> ss", "transitional:     fass.de", "nontransitional:  xn--fa-hia.de"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
 	}
+}
 
-	// Additional cases for strict and decode flags
-	strictTests := []struct {
-		name       string
-		input      string
-		strict     bool
-		punyDecode bool
-		wantErr    bool
-	}{
-		{
-			"non-strict should allow conversion from valid Punycode",
-			"xn--piata-pta",
-			false,
-			true,
-			false,
-		},
-		{
-			"should report conversion error from invalid Punycode",
-			"xn--piata-abc",
-			true,
-			true,
-			true,
-		},
-		{
-			"strict should allow valid UTF-8 input",
-			"piñata", // 'ñ' as single rune
-			true,
-			false,
-			false,
-		},
-		{
-			"strict should block invalid UTF-8 input",
-			string([]rune{
-				'p',
-				'i',
-				'n',
-				0x0303, // 'n' + combining '◌̃' (U+0303)
-				'a',
-				't',
-				'a'},
-			),
-			true,
-			false,
-			true,
-		},
+func TestFormatPlainTextIDNAError(t *testing.T) {
+	data := inspect.NewAnalyzer(inspect.Options{FromPunycode: true}).Analyze("xn--abc-")
+	out := formatPlainText(data, false, false, false)
+	if !strings.Contains(out, "punycode (UTS #46 P4)") {
+		t.Errorf("expected the error code in text output, got:\n%s", out)
 	}
+}
 
-	for _, st := range strictTests {
-		t.Run(st.name, func(t *testing.T) {
-			data := gatherOutputData(st.input, false, st.strict, st.punyDecode, false)
-			if st.wantErr && data.PunycodeError == "" {
-				t.Errorf("expected PunycodeError for strict=%v, input=%q", st.strict, st.input)
-			}
-			if !st.wantErr && data.PunycodeError != "" {
-				t.Errorf("did not expect PunycodeError for strict=%v, input=%q: %s", st.strict, st.input, data.PunycodeError)
-			}
-		})
+func TestFormatPlainTextPolicy(t *testing.T) {
+	pol := &inspect.Policy{Name: "usernames", Scripts: []string{"Latin"}, MaxBytes: 16}
+	data := inspect.NewAnalyzer(inspect.Options{Policy: pol}).Analyze("jаne")
+	out := formatPlainText(data, false, false, false)
+	for _, want := range []string{"policy:       usernames: fail", "  scripts:    fail", "byte 1: Cyrillic script is not allowed", "  max_bytes:  pass"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
 	}
 }
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/eliheady/wtutf/inspect"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	data := inspect.ScanData{
+		Files: 1,
+		Findings: []inspect.ScanFinding{
+			{File: "src/main file.go", Line: 3, Column: 7, CodePoint: "U+202E", Check: "bidi", Rule: "WTUTF001", Severity: inspect.SeverityError, Reason: "unterminated bidi control RIGHT-TO-LEFT OVERRIDE"},
+			{File: "src/main file.go", Line: 4, Column: 1, CodePoint: "U+0663", Check: "test-digits", Rule: "test-digits", Severity: inspect.SeverityNote, Reason: "non-ASCII digit"},
+		},
+	}
+	var b bytes.Buffer
+	if err := writeSARIF(&b, data); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(b.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a single SARIF 2.1.0 run, got %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(inspect.ScanRules()) {
+		t.Errorf("expected %d rules, got %d", len(inspect.ScanRules()), len(run.Tool.Driver.Rules))
+	}
+	if run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("columnKind = %q", run.ColumnKind)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(run.Results))
+	}
+	res := run.Results[0]
+	if res.RuleID != "WTUTF001" || res.RuleIndex != 0 || res.Level != "error" {
+		t.Errorf("unexpected rule in result: %+v", res)
+	}
+	// a check without a scan rule is named by its check name
+	if res := run.Results[1]; res.RuleID != "test-digits" || res.RuleIndex != -1 || res.Level != "note" {
+		t.Errorf("unexpected rule in result: %+v", res)
+	}
+	loc := res.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "src/main%20file.go" {
+		t.Errorf("uri = %q", loc.ArtifactLocation.URI)
+	}
+	if loc.Region.StartLine != 3 || loc.Region.StartColumn != 7 {
+		t.Errorf("region = %+v", loc.Region)
+	}
+}
+
+func TestSARIFURI(t *testing.T) {
+	tests := map[string]string{
+		"main.go":        "main.go",
+		"sub/a.go":       "sub/a.go",
+		"/src/app/a.go":  "file:///src/app/a.go",
+		"odd name #1.go": "odd%20name%20%231.go",
+	}
+	for p, want := range tests {
+		if got := sarifURI(p); got != want {
+			t.Errorf("sarifURI(%q) = %q, want %q", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/eliheady/wtutf/inspect"
+	"github.com/spf13/cobra"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [path...]",
+	Short: "Scan files for bidi controls, invisible characters and mixed-script identifiers",
+	Long: `Walks the given files and directories (the current directory by default) and reports each suspicious code point with its file, line and column: unterminated or unmatched bidi controls (Trojan Source), invisible characters, bytes that are not valid UTF-8, identifiers that mix scripts beyond --check-level, and identifiers that are confusable with ASCII ones. With --idna, every non-ASCII character is also checked against the punycode conversion rules. --checks runs the named checks instead, including checks registered by programs that use the inspect package; checks of a whole string, such as mixed-script and confusable, are run on each identifier that is not plain ASCII.
+
+Binary files are skipped. --include limits the scan to files matching any of the globs, and --exclude skips files and directories matching any of them. Globs are matched against the base name and against the slash-separated path relative to the scanned directory.
+
+Findings are printed one per line by default. --format json (or --json) prints them as JSON, and --format sarif as a SARIF 2.1.0 log for code scanning dashboards, with a stable rule ID for each check.
+
+The exit status has a bit for each kind of finding at or above --fail-on, as for the root command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		accept, err := checkLevelFlag(cmd)
+		if err != nil {
+			return err
+		}
+		failOn, err := failOnFlag(cmd)
+		if err != nil {
+			return err
+		}
+		flags := cmd.Flags()
+		include, _ := flags.GetStringSlice("include")
+		exclude, _ := flags.GetStringSlice("exclude")
+		idnaCheck, _ := flags.GetBool("idna")
+		format, _ := flags.GetString("format")
+		if jsonOut, _ := flags.GetBool("json"); jsonOut {
+			format = "json"
+		}
+		if format != "text" && format != "json" && format != "sarif" {
+			return fmt.Errorf("unknown format %q, want one of: text, json, sarif", format)
+		}
+		checks, err := selectedChecks(cmd, nil)
+		if err != nil {
+			return err
+		}
+		opts := inspect.ScanOptions{Accept: accept, IDNA: idnaCheck, Checks: checks}
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		data := inspect.ScanData{Findings: []inspect.ScanFinding{}}
+		for _, root := range args {
+			if err := inspect.ScanTree(root, include, exclude, opts, &data); err != nil {
+				return err
+			}
+		}
+
+		switch format {
+		case "json":
+			b, err := json.MarshalIndent(data, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+		case "sarif":
+			if err := writeSARIF(cmd.OutOrStdout(), data); err != nil {
+				return err
+			}
+		default:
+			formatScanText(cmd.OutOrStdout(), data)
+		}
+		exitStatus = scanStatus(data, failOn)
+		return nil
+	},
+}
+
+func init() {
+	scanCmd.Flags().StringSlice("include", nil, "Only scan files matching these globs")
+	scanCmd.Flags().StringSlice("exclude", []string{".git"}, "Skip files and directories matching these globs")
+	scanCmd.Flags().String("format", "text", "Output format: text, json or sarif")
+	scanCmd.Flags().Bool("idna", false, "Also report non-ASCII characters that fail the punycode conversion rules")
+	rootCmd.AddCommand(scanCmd)
+}
+
+// formatScanText writes one line per finding in the file:line:column format
+// used by compilers, followed by a count
+func formatScanText(w io.Writer, data inspect.ScanData) {
+	for _, f := range data.Findings {
+		fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n", f.File, f.Line, f.Column, f.CodePoint, f.Reason, f.Rule)
+	}
+	fmt.Fprintf(w, "%d finding(s) in %d file(s)\n", len(data.Findings), data.Files)
+}
+
+// scanStatus returns the exit status for the scan findings
+func scanStatus(data inspect.ScanData, failOn inspect.Severity) (status int) {
+	for _, f := range data.Findings {
+		if f.Severity >= failOn {
+			status |= checkExitCode(f.Check)
+		}
+	}
+	return
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eliheady/wtutf/inspect"
+)
+
+// normalFormSummary lists the normalization forms the input is already in
+func normalFormSummary(forms []inspect.NormalForm) string {
+	var names []string
+	for _, f := range forms {
+		if f.IsNormal {
+			names = append(names, f.Form)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// labelSummary describes a label's conversion for plain text output
+func labelSummary(l inspect.LabelReport) string {
+	if l.Label == "" {
+		return `""`
+	}
+	var b strings.Builder
+	b.WriteString(inspect.PoliteString(l.Label))
+	if l.ALabel != "" && l.ALabel != l.Label {
+		b.WriteString(" -> " + l.ALabel)
+	}
+	if l.ULabel != "" && l.ULabel != l.Label {
+		b.WriteString(" -> " + inspect.PoliteString(l.ULabel))
+	}
+	return b.String()
+}
+
+// describeDeviation describes a deviation character's transitional mapping
+// for plain text output
+func describeDeviation(c inspect.DeviationCharacter) string {
+	mapped := "removed"
+	if c.Transitional != "" {
+		mapped = "-> " + c.Transitional
+	}
+	return fmt.Sprintf("byte %d: %s %s %s", c.Offset, c.CodePoint, c.Name, mapped)
+}
+
+// describeRoundTrip describes an unstable round trip for plain text output
+func describeRoundTrip(input string, r *inspect.RoundTripReport) string {
+	steps := []string{inspect.PoliteString(input), inspect.PoliteString(r.Converted)}
+	if r.Error != "" {
+		steps = append(steps, r.Error)
+	} else {
+		steps = append(steps, inspect.PoliteString(r.RoundTrip))
+	}
+	return strings.Join(steps, " -> ") + " (unstable)"
+}
+
+// idnaErrorSummary writes a classified error as plain text lines
+func idnaErrorSummary(w io.Writer, e *inspect.IDNAError) {
+	code := e.Code
+	if e.IDNACode != "" {
+		code += " (UTS #46 " + e.IDNACode + ")"
+	}
+	fmt.Fprintf(w, "error code:\t%s\n", code)
+	if e.Explanation != "" {
+		fmt.Fprintf(w, "\t%s (%s)\n", e.Explanation, e.Reference)
+	}
+}
+
+// describeFinding describes a check finding for plain text output, with the
+// byte offset of findings about part of the string
+func describeFinding(f inspect.Finding) string {
+	if f.Length == 0 {
+		return fmt.Sprintf("%s (%s): %s", f.Check, f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%s (%s): byte %d: %s", f.Check, f.Severity, f.Offset, f.Message)
+}
+
+// policySummary writes the pass or fail result of each policy rule, with the
+// findings of the rules that failed
+func policySummary(w io.Writer, report *inspect.PolicyReport) {
+	verdict := "pass"
+	if !report.Pass {
+		verdict = "fail"
+	}
+	if report.Name != "" {
+		verdict = report.Name + ": " + verdict
+	}
+	fmt.Fprintf(w, "policy:\t%s\n", verdict)
+	for _, result := range report.Rules {
+		if result.Pass {
+			fmt.Fprintf(w, "  %s:\tpass\n", result.Rule)
+			continue
+		}
+		fmt.Fprintf(w, "  %s:\tfail\n", result.Rule)
+		for _, f := range result.Findings {
+			if f.Length == 0 {
+				fmt.Fprintf(w, "\t  %s\n", f.Message)
+			} else {
+				fmt.Fprintf(w, "\t  byte %d: %s\n", f.Offset, f.Message)
+			}
+		}
+	}
+}
+
+// policyPassed reports whether the string passed the policy, if there was one
+func policyPassed(report *inspect.PolicyReport) bool {
+	return report == nil || report.Pass
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/width"
+)
+
+// displayWidth returns the number of terminal cells s occupies. Widths come
+// from the East Asian Width and emoji presentation properties of each grapheme
+// cluster: wide and fullwidth characters and emoji take two cells, combining
+// marks, joiners and other zero width characters take none. East Asian
+// Ambiguous characters take two cells when ambiguousWide is set, as they do
+// in terminals configured for CJK text, and one otherwise.
+func displayWidth(s string, ambiguousWide bool) (cells int) {
+	state := -1
+	for s != "" {
+		var cluster string
+		var w int
+		cluster, s, w, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if ambiguousWide && w == 1 {
+			r, _ := utf8.DecodeRuneInString(cluster)
+			if width.LookupRune(r).Kind() == width.EastAsianAmbiguous {
+				w = 2
+			}
+		}
+		cells += w
+	}
+	return
+}
+
+// padLeft right aligns s in a field of the given number of terminal cells
+func padLeft(s string, cells int, ambiguousWide bool) string {
+	if w := displayWidth(s, ambiguousWide); w < cells {
+		return strings.Repeat(" ", cells-w) + s
+	}
+	return s
+}
+
+// writeTable writes rows of cells to w in left aligned columns separated by
+// two spaces. Column widths are measured in terminal cells, where
+// text/tabwriter counts runes, so wide and zero width characters line up.
+func writeTable(w io.Writer, rows [][]string, ambiguousWide bool) {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth(cell, ambiguousWide))
+		}
+	}
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell, ambiguousWide)+2))
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name          string
+		s             string
+		ambiguousWide bool
+		want          int
+	}{
+		{"ASCII", "abc", false, 3},
+		{"CJK ideograph", "一", false, 2},
+		{"Hangul syllable", "한", false, 2},
+		{"fullwidth letter", "Ａ", false, 2},
+		{"halfwidth katakana", "ｱ", false, 1},
+		{"emoji", "😀", false, 2},
+		{"ZWJ sequence", "👨‍👩‍👧", false, 2},
+		{"emoji presentation selector", "☺️", false, 2},
+		{"text presentation", "☺", false, 1},
+		{"combining mark", "é", false, 1},
+		{"dotted circle with mark", "◌́", false, 1},
+		{"zero width space", "​", false, 0},
+		{"ambiguous narrow", "ß", false, 1},
+		{"ambiguous wide", "ß", true, 2},
+		{"ambiguous wide leaves narrow alone", "a", true, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := displayWidth(tc.s, tc.ambiguousWide); got != tc.want {
+				t.Errorf("displayWidth(%q, %t) = %d, want %d", tc.s, tc.ambiguousWide, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var b bytes.Buffer
+	writeTable(&b, [][]string{
+		{padLeft("一", 3, false), "wide", ""},
+		{padLeft("a", 3, false), "narrow", "note"},
+		{padLeft("◌́", 3, false), "mark", ""},
+	}, false)
+
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		// the second column starts after 3 cells and 2 spaces of padding
+		col := strings.Index(line, strings.Fields(line)[1])
+		if got := displayWidth(line[:col], false); got != 5 {
+			t.Errorf("line %d: second column starts at cell %d, want 5: %q", i, got, line)
+		}
+		if strings.HasSuffix(line, " ") {
+			t.Errorf("line %d has trailing spaces: %q", i, line)
+		}
+	}
+}
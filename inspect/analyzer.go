@@ -0,0 +1,310 @@
+// Package inspect analyzes strings the way the wtutf command does: it converts
+// them to and from punycode, breaks them down rune by rune, and looks for the
+// mixed scripts, confusable characters, invisible characters and bidi
+// controls that make text look different from what it is.
+//
+// An Analyzer runs the analyses selected in its Options and returns an
+// OutputData, the same model wtutf prints as JSON:
+//
+//	a := inspect.NewAnalyzer(inspect.Options{Strict: true, Confusables: true})
+//	data := a.Analyze("ցooցlе.com")
+//	if data.PunycodeError != "" || data.Confusables.ASCIILike {
+//		// reject the name
+//	}
+//
+// The individual checks are also available as functions, for example
+// Restriction, Confusables and Bidi.
+package inspect
+
+import (
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// Options selects what an Analyzer does. The zero value converts the input
+// to punycode with wtutf's default rules, counts its bytes and characters,
+// converts the result back to check the round trip, and converts any
+// deviation characters with transitional and nontransitional processing.
+type Options struct {
+	// Strict adds the registration and STD3 rules to the conversion rules
+	Strict bool
+	// Transitional maps the input as UTS #46 lookup does, with transitional
+	// processing of the deviation characters
+	Transitional bool
+	// FromPunycode decodes the input from punycode instead of encoding it
+	FromPunycode bool
+
+	// Ranges fills in UnicodeRanges and Restriction
+	Ranges bool
+	// Table fills in a row per code point
+	Table bool
+	// Properties adds the Unicode properties of each code point to the
+	// table, and implies Table
+	Properties bool
+	// Graphemes segments the input into grapheme clusters
+	Graphemes bool
+	// Normalization shows the input under each normalization form
+	Normalization bool
+	// Confusables computes the UTS #39 confusable skeleton
+	Confusables bool
+	// Labels converts each label of a domain name separately
+	Labels bool
+	// Profiles converts the input under each of the idna package's profiles
+	Profiles bool
+	// PRECIS enforces the PRECIS username, password and nickname profiles
+	PRECIS bool
+	// Bidi reports unterminated and unmatched bidi controls
+	Bidi bool
+	// Watchlist, when set, is checked for protected names the input is
+	// confusable with
+	Watchlist *Watchlist
+	// Checks are run over the input and reported in Findings, and in the
+	// table for findings about a single code point
+	Checks []Check
+	// Policy, when set, is evaluated rule by rule
+	Policy *Policy
+}
+
+// Analyzer analyzes strings with a fixed set of Options. It is safe for
+// concurrent use.
+type Analyzer struct {
+	opts   Options
+	rules  []idna.Option
+	policy []Check
+}
+
+// NewAnalyzer returns an Analyzer for the given options
+func NewAnalyzer(opts Options) *Analyzer {
+	opts.Table = opts.Table || opts.Properties
+	a := &Analyzer{
+		opts:  opts,
+		rules: ConversionRules(opts.Strict, opts.Transitional),
+	}
+	if opts.Policy != nil {
+		a.policy = opts.Policy.Checks()
+	}
+	return a
+}
+
+// Options returns the options the Analyzer was created with
+func (a *Analyzer) Options() Options {
+	return a.opts
+}
+
+// ToASCII converts s to punycode with the Analyzer's conversion rules
+func (a *Analyzer) ToASCII(s string) (string, error) {
+	return toPuny(s, a.rules)
+}
+
+// ToUnicode decodes punycode with the Analyzer's conversion rules
+func (a *Analyzer) ToUnicode(s string) (string, error) {
+	return fromPuny(s, a.rules)
+}
+
+// OutputData holds the structured output for both text and JSON formats
+type OutputData struct {
+	Input         string            `json:"input"`
+	Punycode      string            `json:"punycode,omitempty"`
+	UTF8          string            `json:"utf8,omitempty"`
+	PunycodeError string            `json:"punycode_error,omitempty"`
+	IDNAError     *IDNAError        `json:"idna_error,omitempty"`
+	Deviations    *DeviationReport  `json:"deviations,omitempty"`
+	RoundTrip     *RoundTripReport  `json:"round_trip,omitempty"`
+	TotalBytes    int               `json:"total_bytes"`
+	Characters    int               `json:"characters"`
+	GraphemeCount int               `json:"grapheme_count,omitempty"`
+	Graphemes     []GraphemeCluster `json:"graphemes,omitempty"`
+	Labels        []LabelReport     `json:"labels,omitempty"`
+	Profiles      []ProfileResult   `json:"profiles,omitempty"`
+	PRECIS        []PRECISResult    `json:"precis,omitempty"`
+	UnicodeRanges map[string]int    `json:"unicode_ranges,omitempty"`
+	Restriction   string            `json:"restriction_level,omitempty"`
+	InvalidUTF8   []InvalidSequence `json:"invalid_utf8,omitempty"`
+	Normalization []NormalForm      `json:"normalization,omitempty"`
+	Confusables   *ConfusableReport `json:"confusables,omitempty"`
+	Watchlist     []WatchlistMatch  `json:"watchlist,omitempty"`
+	Bidi          *BidiReport       `json:"bidi,omitempty"`
+	Findings      []Finding         `json:"findings,omitempty"`
+	Policy        *PolicyReport     `json:"policy,omitempty"`
+	Table         []RuneTableRow    `json:"table,omitempty"`
+}
+
+// RuneTableRow describes one code point of the input, or one invalid UTF-8
+// sequence
+type RuneTableRow struct {
+	Offset    int      `json:"offset"`
+	Printable string   `json:"printable"`
+	CodePoint string   `json:"code_point"`
+	Bytes     string   `json:"bytes"`
+	Length    int      `json:"length"`
+	Scripts   []string `json:"scripts,omitempty"`
+	Invalid   string   `json:"invalid,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+	Watchlist []string `json:"watchlist,omitempty"`
+	Findings  []string `json:"findings,omitempty"`
+	PRECIS    []string `json:"precis,omitempty"`
+	*RuneProperties
+}
+
+type runeCache struct {
+	printable string
+	padded    string
+	bytes     string
+	scripts   []string
+	errors    []string
+}
+
+// Analyze collects all output data for a given input string
+func (a *Analyzer) Analyze(ustring string) OutputData {
+	// when decoding punycode, ustring becomes the decoded string and the
+	// analyses describe it. The round trip and the labels convert in both
+	// directions, so they start from the input as given.
+	input := ustring
+	data := OutputData{
+		Input:       ustring,
+		TotalBytes:  len(ustring),
+		Characters:  countCharacters(ustring),
+		InvalidUTF8: FindInvalidUTF8(ustring),
+	}
+
+	var punyConverted bool
+
+	if a.opts.FromPunycode {
+		if utfString, err := a.ToUnicode(ustring); err == nil {
+			data.Punycode = ustring
+			data.UTF8 = utfString
+			ustring = utfString
+		} else {
+			data.PunycodeError = "could not decode punycode input: " + err.Error()
+			data.IDNAError = ClassifyIDNAError(ustring, err)
+		}
+	} else {
+		if punycode, err := a.ToASCII(ustring); err == nil {
+			punyConverted = true
+			data.Punycode = punycode
+		} else {
+			data.PunycodeError = "could not punycode-convert input: " + err.Error()
+			data.IDNAError = ClassifyIDNAError(ustring, err)
+		}
+	}
+
+	data.RoundTrip = a.RoundTrip(input)
+	data.Deviations = a.Deviations(ustring)
+
+	if a.opts.Ranges {
+		data.UnicodeRanges = ListRanges(ustring)
+		data.Restriction = Restriction(ustring).String()
+	}
+
+	if a.opts.Table {
+		data.Table = runeTable(ustring, punyConverted)
+	}
+	if a.opts.Properties {
+		addRuneProperties(data.Table, ustring)
+	}
+	if a.opts.Graphemes {
+		data.Graphemes = Graphemes(ustring)
+		data.GraphemeCount = len(data.Graphemes)
+	}
+	if a.opts.Normalization {
+		data.Normalization = NormalizationForms(ustring)
+	}
+	if a.opts.Confusables {
+		data.Confusables = Confusables(ustring)
+	}
+	if a.opts.Labels {
+		data.Labels = a.Labels(input)
+	}
+	if a.opts.Profiles {
+		data.Profiles = Profiles(ustring)
+	}
+	if a.opts.PRECIS {
+		data.PRECIS = PRECIS(ustring)
+		markPRECISRows(data.Table, data.PRECIS)
+	}
+	if a.opts.Bidi {
+		data.Bidi = Bidi(ustring)
+	}
+	if a.opts.Watchlist != nil {
+		data.Watchlist = a.opts.Watchlist.Match(ustring)
+		markWatchlistRows(data.Table, data.Watchlist)
+	}
+	if len(a.opts.Checks) > 0 {
+		data.Findings = RunChecks(ustring, a.opts.Checks)
+		markFindingRows(data.Table, data.Findings)
+	}
+	if a.opts.Policy != nil {
+		data.Policy = a.opts.Policy.evaluate(a.policy, ustring)
+		for _, result := range data.Policy.Rules {
+			markFindingRows(data.Table, result.Findings)
+		}
+	}
+	return data
+}
+
+// markFindingRows notes on each table row the findings about that code point
+// alone
+func markFindingRows(table []RuneTableRow, findings []Finding) {
+	rows := map[int]int{}
+	for i, row := range table {
+		rows[row.Offset] = i
+	}
+	for _, f := range findings {
+		i, ok := rows[f.Offset]
+		if !ok || f.Length != table[i].Length {
+			continue
+		}
+		table[i].Findings = append(table[i].Findings, f.Check+": "+f.Message)
+	}
+}
+
+// runeTable describes each code point of s. The conversion rules a rune
+// fails are only looked up when the string as a whole did not convert.
+func runeTable(ustring string, punyConverted bool) (table []RuneTableRow) {
+	cache := map[rune]runeCache{}
+	for offset := 0; offset < len(ustring); {
+		r, size := utf8.DecodeRuneInString(ustring[offset:])
+		if r == utf8.RuneError && size == 1 {
+			// invalid sequences are shown as their raw bytes rather than
+			// the U+FFFD replacement character
+			n, reason := classifyInvalid(ustring[offset:])
+			table = append(table, RuneTableRow{
+				Offset:    offset,
+				Printable: "^?",
+				CodePoint: "invalid",
+				Bytes:     hex.EncodeToString([]byte(ustring[offset : offset+n])),
+				Length:    n,
+				Invalid:   reason,
+			})
+			offset += n
+			continue
+		}
+		rc, ok := cache[r]
+		if !ok {
+			rc = runeCache{
+				printable: PoliteString(string(r)),
+				padded:    fmt.Sprintf("%#0*x", (utf8.RuneLen(r) * 2), r),
+				bytes:     hex.EncodeToString([]byte(string(r))),
+				scripts:   FindRange(r),
+			}
+			if !punyConverted {
+				rc.errors = EnumerateErrors(r)
+			}
+			cache[r] = rc
+		}
+		table = append(table, RuneTableRow{
+			Offset:    offset,
+			Printable: rc.printable,
+			CodePoint: rc.padded,
+			Bytes:     rc.bytes,
+			Length:    size,
+			Scripts:   rc.scripts,
+			Errors:    rc.errors,
+		})
+		offset += size
+	}
+	return
+}
@@ -0,0 +1,146 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		showRanges bool
+		wantRunes  []string
+	}{
+		{
+			name:       "basic table",
+			input:      "café",
+			showRanges: false,
+			wantRunes:  []string{"c", "a", "f", "é"},
+		},
+		{
+			name:       "table with unicode ranges",
+			input:      "café",
+			showRanges: true,
+			wantRunes:  []string{"c", "a", "f", "é"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := NewAnalyzer(Options{Ranges: tc.showRanges, Table: true}).Analyze(tc.input)
+			if data.Input != tc.input {
+				t.Errorf("expected input %q, got %q", tc.input, data.Input)
+			}
+			if len(data.Table) == 0 {
+				t.Errorf("expected non-empty Table, got empty")
+			}
+			for _, wantRune := range tc.wantRunes {
+				found := false
+				for _, row := range data.Table {
+					if strings.TrimSpace(row.Printable) == wantRune {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected rune %q not found in Table: %+v", wantRune, data.Table)
+				}
+			}
+			if tc.showRanges && len(data.UnicodeRanges) == 0 {
+				t.Errorf("expected UnicodeRanges to be populated when showRanges is true")
+			}
+			if !tc.showRanges && len(data.UnicodeRanges) != 0 {
+				t.Errorf("expected UnicodeRanges to be empty when showRanges is false")
+			}
+		})
+	}
+
+	// Additional cases for strict and decode flags
+	strictTests := []struct {
+		name       string
+		input      string
+		strict     bool
+		punyDecode bool
+		wantErr    bool
+	}{
+		{
+			"non-strict should allow conversion from valid Punycode",
+			"xn--piata-pta",
+			false,
+			true,
+			false,
+		},
+		{
+			"should report conversion error from invalid Punycode",
+			"xn--piata-abc",
+			true,
+			true,
+			true,
+		},
+		{
+			"strict should allow valid UTF-8 input",
+			"piñata", // 'ñ' as single rune
+			true,
+			false,
+			false,
+		},
+		{
+			"strict should block invalid UTF-8 input",
+			string([]rune{
+				'p',
+				'i',
+				'n',
+				0x0303, // 'n' + combining '◌̃' (U+0303)
+				'a',
+				't',
+				'a'},
+			),
+			true,
+			false,
+			true,
+		},
+	}
+
+	for _, st := range strictTests {
+		t.Run(st.name, func(t *testing.T) {
+			data := NewAnalyzer(Options{Strict: st.strict, FromPunycode: st.punyDecode}).Analyze(st.input)
+			if st.wantErr && data.PunycodeError == "" {
+				t.Errorf("expected PunycodeError for strict=%v, input=%q", st.strict, st.input)
+			}
+			if !st.wantErr && data.PunycodeError != "" {
+				t.Errorf("did not expect PunycodeError for strict=%v, input=%q: %s", st.strict, st.input, data.PunycodeError)
+			}
+		})
+	}
+}
+
+func TestAnalyzeDecodedPunycode(t *testing.T) {
+	opts := Options{FromPunycode: true, Normalization: true, Confusables: true, Profiles: true, Bidi: true}
+	data := NewAnalyzer(opts).Analyze("xn--pypal-4ve")
+	if data.Confusables == nil || data.Confusables.Skeleton != "paypal" || !data.Confusables.ASCIILike {
+		t.Errorf("Confusables = %+v, want the skeleton of the decoded pаypal", data.Confusables)
+	}
+	if len(data.Normalization) == 0 || data.Normalization[0].Output != "pаypal" {
+		t.Errorf("Normalization = %+v, want the decoded string", data.Normalization)
+	}
+	if len(data.Profiles) == 0 || data.Profiles[0].ULabel != "pаypal" {
+		t.Errorf("Profiles = %+v, want the decoded string converted", data.Profiles)
+	}
+	if data.Bidi == nil {
+		t.Error("expected a bidi report")
+	}
+}
+
+func ExampleAnalyzer() {
+	a := NewAnalyzer(Options{Strict: true, Ranges: true, Confusables: true})
+	data := a.Analyze("ցooցlе.com")
+	fmt.Println(data.Punycode)
+	fmt.Println(data.Restriction)
+	fmt.Println(data.Confusables.Skeleton, data.Confusables.ASCIILike)
+	// Output:
+	// xn--ool-tdd07nca.com
+	// minimally-restrictive
+	// google.corn true
+}
@@ -0,0 +1,114 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/unicode/bidi"
+)
+
+// BidiReport counts the explicit directional formatting characters in the
+// input and lists the ones that are not properly terminated
+type BidiReport struct {
+	Controls int         `json:"controls"`
+	Issues   []BidiIssue `json:"issues,omitempty"`
+}
+
+// BidiIssue is a directional formatting character that is either never
+// terminated within its paragraph ("unterminated") or that terminates
+// nothing ("unmatched")
+type BidiIssue struct {
+	Offset    int    `json:"offset"`
+	CodePoint string `json:"code_point"`
+	Name      string `json:"name"`
+	Problem   string `json:"problem"`
+}
+
+// the explicit directional formatting characters of UAX #9
+const (
+	bidiLRE = '\u202A'
+	bidiRLE = '\u202B'
+	bidiPDF = '\u202C'
+	bidiLRO = '\u202D'
+	bidiRLO = '\u202E'
+	bidiLRI = '\u2066'
+	bidiRLI = '\u2067'
+	bidiFSI = '\u2068'
+	bidiPDI = '\u2069'
+)
+
+// IsExplicitBidi reports whether r is one of the embedding, override or
+// isolate characters that Bidi pairs up
+func IsExplicitBidi(r rune) bool {
+	return bidiLRE <= r && r <= bidiRLO || bidiLRI <= r && r <= bidiPDI
+}
+
+// Bidi walks a string the way UAX #9 pairs directional formatting
+// characters. Embeddings and overrides are closed by PDF, isolates by PDI,
+// and a PDI also closes any embeddings opened inside its isolate. A PDF
+// cannot close an embedding from outside the current isolate. Everything still
+// open at a paragraph separator or the end of the string is unterminated. These
+// are the patterns used by Trojan Source attacks (CVE-2021-42574) to make text
+// display in a different order than it is parsed.
+func Bidi(s string) *BidiReport {
+	report := &BidiReport{}
+	type opened struct {
+		offset  int
+		r       rune
+		isolate bool
+	}
+	var stack []opened
+	issue := func(offset int, r rune, problem string) {
+		report.Issues = append(report.Issues, BidiIssue{
+			Offset:    offset,
+			CodePoint: fmt.Sprintf("%U", r),
+			Name:      RuneName(r),
+			Problem:   problem,
+		})
+	}
+	unterminated := func() {
+		for _, o := range stack {
+			issue(o.offset, o.r, "unterminated")
+		}
+		stack = nil
+	}
+
+	for i, r := range s {
+		switch r {
+		case bidiLRE, bidiRLE, bidiLRO, bidiRLO:
+			report.Controls++
+			stack = append(stack, opened{i, r, false})
+		case bidiLRI, bidiRLI, bidiFSI:
+			report.Controls++
+			stack = append(stack, opened{i, r, true})
+		case bidiPDF:
+			report.Controls++
+			if len(stack) == 0 || stack[len(stack)-1].isolate {
+				issue(i, r, "unmatched")
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		case bidiPDI:
+			report.Controls++
+			top := len(stack) - 1
+			for top >= 0 && !stack[top].isolate {
+				top--
+			}
+			if top < 0 {
+				issue(i, r, "unmatched")
+				continue
+			}
+			stack = stack[:top]
+		default:
+			if props, _ := bidi.LookupRune(r); props.Class() == bidi.B {
+				unterminated()
+			}
+		}
+	}
+	unterminated()
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].Offset < report.Issues[j].Offset
+	})
+	return report
+}
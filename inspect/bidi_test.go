@@ -0,0 +1,78 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBidiReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		controls int
+		want     []BidiIssue
+	}{
+		{
+			name:  "no controls",
+			input: "plain text",
+		},
+		{
+			name:     "terminated override",
+			input:    "a\u202Ebc\u202Cd",
+			controls: 2,
+		},
+		{
+			name:     "terminated isolate",
+			input:    "a\u2067bc\u2069d",
+			controls: 2,
+		},
+		{
+			name:     "unterminated override",
+			input:    "user\u202E // admin",
+			controls: 1,
+			want:     []BidiIssue{{4, "U+202E", "RIGHT-TO-LEFT OVERRIDE", "unterminated"}},
+		},
+		{
+			name:     "PDI closes embeddings inside its isolate",
+			input:    "\u2066a\u202Bb\u2069",
+			controls: 3,
+		},
+		{
+			name:     "PDF cannot close an embedding outside the isolate",
+			input:    "\u202Aa\u2068b\u202C",
+			controls: 3,
+			want: []BidiIssue{
+				{0, "U+202A", "LEFT-TO-RIGHT EMBEDDING", "unterminated"},
+				{4, "U+2068", "FIRST STRONG ISOLATE", "unterminated"},
+				{8, "U+202C", "POP DIRECTIONAL FORMATTING", "unmatched"},
+			},
+		},
+		{
+			name:     "unmatched PDI",
+			input:    "a\u2069",
+			controls: 1,
+			want:     []BidiIssue{{1, "U+2069", "POP DIRECTIONAL ISOLATE", "unmatched"}},
+		},
+		{
+			name:     "paragraph separator ends the embedding",
+			input:    "a\u202Db\n\u202Cc",
+			controls: 2,
+			want: []BidiIssue{
+				{1, "U+202D", "LEFT-TO-RIGHT OVERRIDE", "unterminated"},
+				{6, "U+202C", "POP DIRECTIONAL FORMATTING", "unmatched"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Bidi(tc.input)
+			if got.Controls != tc.controls {
+				t.Errorf("Controls = %d, want %d", got.Controls, tc.controls)
+			}
+			if !reflect.DeepEqual(got.Issues, tc.want) {
+				t.Errorf("Issues = %+v, want %+v", got.Issues, tc.want)
+			}
+		})
+	}
+}
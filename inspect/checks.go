@@ -0,0 +1,287 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Severity ranks findings. The names match the SARIF result levels.
+type Severity int
+
+const (
+	// SeverityNote is for findings worth knowing about
+	SeverityNote Severity = iota
+	// SeverityWarning is for findings that may be a problem
+	SeverityWarning
+	// SeverityError is for findings that are a problem
+	SeverityError
+)
+
+var severityNames = []string{"note", "warning", "error"}
+
+// String returns the name of the severity, as ParseSeverity takes it
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// MarshalText encodes a Severity as its name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a Severity from its name
+func (s *Severity) UnmarshalText(text []byte) error {
+	var err error
+	*s, err = ParseSeverity(string(text))
+	return err
+}
+
+// ParseSeverity takes a severity name as printed by String
+func ParseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if s == name {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q, want one of: %s", s, strings.Join(severityNames, ", "))
+}
+
+// Check is one test a string can fail. A check reports problems with the
+// string as a whole, or with spans of it, from CheckString, and problems with
+// single code points from CheckRune. Either may find nothing.
+type Check interface {
+	// Name identifies the check in findings and on the command line
+	Name() string
+	// Severity is the severity of every finding the check reports
+	Severity() Severity
+	// CheckString returns findings about s. RunChecks fills in their Check
+	// and Severity fields.
+	CheckString(s string) []Finding
+	// CheckRune returns a message for each problem with r
+	CheckRune(r rune) []string
+}
+
+// Finding is a problem a Check found. Offset and Length give the bytes of the
+// input it is about; Length is 0 for findings about the string as a whole.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Offset   int      `json:"offset"`
+	Length   int      `json:"length,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// checkRegistry holds the checks available by name. It is filled in by init
+// functions, so it is not guarded for concurrent registration.
+var checkRegistry = map[string]Check{}
+
+// RegisterCheck makes a check available to LookupCheck under its name. It is
+// meant to be called from init functions, and panics if the name is taken.
+func RegisterCheck(c Check) {
+	name := c.Name()
+	if _, dup := checkRegistry[name]; dup {
+		panic("inspect: check registered twice: " + name)
+	}
+	checkRegistry[name] = c
+}
+
+// LookupCheck returns the registered check with the given name
+func LookupCheck(name string) (Check, bool) {
+	c, ok := checkRegistry[name]
+	return c, ok
+}
+
+// CheckNames returns the names of the registered checks, sorted
+func CheckNames() []string {
+	names := make([]string, 0, len(checkRegistry))
+	for name := range checkRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunChecks runs each check over s and returns the findings in check order.
+// The rune checks are given each valid code point of s; invalid UTF-8 is
+// left to the utf8 check.
+func RunChecks(s string, checks []Check) (findings []Finding) {
+	for _, c := range checks {
+		for _, f := range c.CheckString(s) {
+			f.Check = c.Name()
+			f.Severity = c.Severity()
+			findings = append(findings, f)
+		}
+		for offset := 0; offset < len(s); {
+			r, size := utf8.DecodeRuneInString(s[offset:])
+			if r != utf8.RuneError || size > 1 {
+				for _, msg := range c.CheckRune(r) {
+					findings = append(findings, Finding{
+						Check:    c.Name(),
+						Severity: c.Severity(),
+						Offset:   offset,
+						Length:   size,
+						Message:  msg,
+					})
+				}
+			}
+			offset += size
+		}
+	}
+	return
+}
+
+// funcCheck is a Check made of a pair of functions, either of which may be
+// nil
+type funcCheck struct {
+	name        string
+	severity    Severity
+	checkString func(string) []Finding
+	checkRune   func(rune) []string
+}
+
+// NewCheck returns a Check that calls checkString and checkRune, either of
+// which may be nil. It is the simplest way to add a check of your own:
+//
+//	inspect.RegisterCheck(inspect.NewCheck("no-digits", inspect.SeverityWarning, nil,
+//		func(r rune) []string {
+//			if unicode.IsDigit(r) {
+//				return []string{"digit"}
+//			}
+//			return nil
+//		}))
+func NewCheck(name string, severity Severity, checkString func(string) []Finding, checkRune func(rune) []string) Check {
+	return &funcCheck{name, severity, checkString, checkRune}
+}
+
+func (c *funcCheck) Name() string       { return c.name }
+func (c *funcCheck) Severity() Severity { return c.severity }
+
+func (c *funcCheck) CheckString(s string) []Finding {
+	if c.checkString == nil {
+		return nil
+	}
+	return c.checkString(s)
+}
+
+func (c *funcCheck) CheckRune(r rune) []string {
+	if c.checkRune == nil {
+		return nil
+	}
+	return c.checkRune(r)
+}
+
+// the built-in checks share their names with the scan checks, and the
+// watchlist check starts out with no protected names
+func init() {
+	RegisterCheck(NewCheck("utf8", SeverityError, checkUTF8, nil))
+	RegisterCheck(IDNACheck(false, false))
+	RegisterCheck(NewCheck("invisible", SeverityWarning, nil, checkInvisible))
+	RegisterCheck(NewCheck("bidi", SeverityError, checkBidi, nil))
+	RegisterCheck(MixedScriptCheck(HighlyRestrictive))
+	RegisterCheck(NewCheck("confusable", SeverityError, checkConfusable, nil))
+	RegisterCheck(WatchlistCheck(nil))
+}
+
+func checkUTF8(s string) (findings []Finding) {
+	for _, seq := range FindInvalidUTF8(s) {
+		findings = append(findings, Finding{
+			Offset:  seq.Offset,
+			Length:  len(seq.Bytes) / 2,
+			Message: fmt.Sprintf("invalid UTF-8 %s (%s)", seq.Bytes, seq.Reason),
+		})
+	}
+	return
+}
+
+// IDNACheck returns the "idna" check, which reports a string that does not
+// convert to punycode under wtutf's conversion rules. The rules each
+// character fails are already in RuneTableRow.Errors.
+func IDNACheck(strict, transitional bool) Check {
+	rules := ConversionRules(strict, transitional)
+	return NewCheck("idna", SeverityError, func(s string) []Finding {
+		if _, err := toPuny(s, rules); err != nil {
+			return []Finding{{Message: fmt.Sprintf("%s [%s]", err, ClassifyIDNAError(s, err).Code)}}
+		}
+		return nil
+	}, nil)
+}
+
+// checkInvisible reports default ignorable code points. The explicit bidi
+// controls are left to the bidi check.
+func checkInvisible(r rune) []string {
+	if unicode.Is(defaultIgnorable, r) && !IsExplicitBidi(r) {
+		return []string{"invisible character " + RuneName(r)}
+	}
+	return nil
+}
+
+func checkBidi(s string) (findings []Finding) {
+	for _, issue := range Bidi(s).Issues {
+		_, size := utf8.DecodeRuneInString(s[issue.Offset:])
+		findings = append(findings, Finding{
+			Offset:  issue.Offset,
+			Length:  size,
+			Message: fmt.Sprintf("%s bidi control %s %s", issue.Problem, issue.CodePoint, issue.Name),
+		})
+	}
+	return
+}
+
+// MixedScriptCheck returns the "mixed-script" check, which reports a string
+// whose UTS #39 restriction level is less restrictive than accept. A string
+// with a character outside the identifier profile is Unrestricted however
+// few scripts it uses, and the finding names the character.
+func MixedScriptCheck(accept RestrictionLevel) Check {
+	return NewCheck("mixed-script", SeverityError, func(s string) []Finding {
+		level := Restriction(s)
+		if level <= accept {
+			return nil
+		}
+		if char, ok := outsideIdentifierProfile(s); ok {
+			// the level is unrestricted whatever the scripts are
+			return []Finding{{Message: fmt.Sprintf("restriction level %s: %s is outside the identifier profile", level, char)}}
+		}
+		var scripts []string
+		for name := range ListRanges(s) {
+			if name != "Common" && name != "Inherited" {
+				scripts = append(scripts, name)
+			}
+		}
+		sort.Strings(scripts)
+		msg := fmt.Sprintf("restriction level %s", level)
+		if len(scripts) > 0 {
+			msg += ": " + strings.Join(scripts, ", ")
+		}
+		return []Finding{{Message: msg}}
+	}, nil)
+}
+
+func checkConfusable(s string) []Finding {
+	if report := Confusables(s); report.ASCIILike {
+		// the skeleton is only for comparing, and can differ from the
+		// name the string imitates: m becomes rn
+		return []Finding{{Message: "has the ASCII-like skeleton " + PoliteString(report.Skeleton)}}
+	}
+	return nil
+}
+
+// WatchlistCheck returns the "watchlist" check, which reports the parts of a
+// string that are confusable with a protected name. The watchlist may be nil.
+func WatchlistCheck(wl *Watchlist) Check {
+	return NewCheck("watchlist", SeverityError, func(s string) (findings []Finding) {
+		for _, m := range wl.Match(s) {
+			findings = append(findings, Finding{
+				Offset:  m.Offset,
+				Length:  len(m.Matched),
+				Message: "confusable with protected name " + PoliteString(m.Name),
+			})
+		}
+		return
+	}, nil)
+}
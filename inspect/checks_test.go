@@ -0,0 +1,134 @@
+package inspect
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestBuiltinChecks(t *testing.T) {
+	tests := []struct {
+		check string
+		input string
+		want  []Finding
+	}{
+		{"utf8", "a\xffb", []Finding{{"utf8", SeverityError, 1, 1, "invalid UTF-8 ff (invalid byte 0xff)"}}},
+		{"utf8", "abc", nil},
+		{"idna", "a_b", nil},
+		{"idna", "-bad", []Finding{{"idna", SeverityError, 0, 0, `idna: invalid label "-bad" [hyphen]`}}},
+		{"invisible", "pay\u00adpal", []Finding{{"invisible", SeverityWarning, 3, 2, "invisible character SOFT HYPHEN"}}},
+		{"invisible", "x\u2067y", nil},
+		{"bidi", "x\u2067y", []Finding{{"bidi", SeverityError, 1, 3, "unterminated bidi control U+2067 RIGHT-TO-LEFT ISOLATE"}}},
+		{"bidi", "x\u2067y\u2069", nil},
+		{"mixed-script", "aα", []Finding{{"mixed-script", SeverityError, 0, 0, "restriction level minimally-restrictive: Greek, Latin"}}},
+		{"mixed-script", "Go言語のテスト", nil},
+		{"mixed-script", "pay\u00adpal", []Finding{{"mixed-script", SeverityError, 0, 0, "restriction level unrestricted: U+00AD SOFT HYPHEN is outside the identifier profile"}}},
+		{"mixed-script", "caf\xc0é", []Finding{{"mixed-script", SeverityError, 0, 0, "restriction level unrestricted: invalid UTF-8 is outside the identifier profile"}}},
+		{"confusable", "аррӏе", []Finding{{"confusable", SeverityError, 0, 0, "has the ASCII-like skeleton apple"}}},
+		{"confusable", "apple", nil},
+		{"watchlist", "paypal", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.check+" "+tc.input, func(t *testing.T) {
+			c, ok := LookupCheck(tc.check)
+			if !ok {
+				t.Fatalf("check %q is not registered", tc.check)
+			}
+			if got := RunChecks(tc.input, []Check{c}); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("RunChecks(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConfiguredChecks(t *testing.T) {
+	if got := RunChecks("aα", []Check{MixedScriptCheck(MinimallyRestrictive)}); len(got) != 0 {
+		t.Errorf("expected Latin+Greek to pass at minimally-restrictive, got %+v", got)
+	}
+	if got := RunChecks("a_b", []Check{IDNACheck(true, false)}); len(got) != 1 || !strings.HasSuffix(got[0].Message, "[std3]") {
+		t.Errorf("expected the strict rules to reject a_b, got %+v", got)
+	}
+
+	wl, err := ParseWatchlist(strings.NewReader("paypal\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Finding{{"watchlist", SeverityError, 0, 7, "confusable with protected name paypal"}}
+	if got := RunChecks("pаypal", []Check{WatchlistCheck(wl)}); !reflect.DeepEqual(got, want) {
+		t.Errorf("WatchlistCheck = %+v, want %+v", got, want)
+	}
+}
+
+func TestRegisterCheck(t *testing.T) {
+	digits := NewCheck("test-digits", SeverityNote, nil, func(r rune) []string {
+		if unicode.IsDigit(r) {
+			return []string{"digit"}
+		}
+		return nil
+	})
+	RegisterCheck(digits)
+	defer delete(checkRegistry, "test-digits")
+
+	c, ok := LookupCheck("test-digits")
+	if !ok {
+		t.Fatal("registered check not found")
+	}
+	want := []Finding{{"test-digits", SeverityNote, 1, 2, "digit"}}
+	if got := RunChecks("a٣\xff", []Check{c}); !reflect.DeepEqual(got, want) {
+		t.Errorf("RunChecks = %+v, want %+v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic registering a name twice")
+		}
+	}()
+	RegisterCheck(digits)
+}
+
+func TestSeverityJSON(t *testing.T) {
+	b, err := json.Marshal(Finding{Check: "bidi", Severity: SeverityWarning, Message: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"check":"bidi","severity":"warning","offset":0,"message":"m"}`; string(b) != want {
+		t.Errorf("json = %s, want %s", b, want)
+	}
+	var f Finding
+	if err := json.Unmarshal(b, &f); err != nil || f.Severity != SeverityWarning {
+		t.Errorf("decoded %+v, %v", f, err)
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
+
+func TestSeverityString(t *testing.T) {
+	for s, want := range map[Severity]string{SeverityNote: "note", SeverityError: "error", -1: "Severity(-1)", 7: "Severity(7)"} {
+		if got := s.String(); got != want {
+			t.Errorf("Severity(%d).String() = %q, want %q", int(s), got, want)
+		}
+	}
+}
+
+func TestAnalyzeChecks(t *testing.T) {
+	invisible, _ := LookupCheck("invisible")
+	mixed, _ := LookupCheck("mixed-script")
+	data := NewAnalyzer(Options{Table: true, Checks: []Check{invisible, mixed}}).Analyze("a\u200bα")
+	if len(data.Findings) != 2 {
+		t.Fatalf("expected two findings, got %+v", data.Findings)
+	}
+	// only the finding about a single code point is marked in the table
+	for i, row := range data.Table {
+		want := 0
+		if i == 1 {
+			want = 1
+		}
+		if len(row.Findings) != want {
+			t.Errorf("row %d findings = %q, want %d", i, row.Findings, want)
+		}
+	}
+}
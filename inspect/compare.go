@@ -0,0 +1,172 @@
+package inspect
+
+import (
+	"encoding/hex"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// CompareData holds the structured output of the compare subcommand
+type CompareData struct {
+	A           string        `json:"a"`
+	B           string        `json:"b"`
+	Identical   bool          `json:"identical"`
+	Equivalence []Equivalence `json:"equivalence"`
+	Runes       []DiffOp      `json:"runes"`
+	Graphemes   []DiffOp      `json:"graphemes"`
+}
+
+// Equivalence reports whether two strings match after a transformation
+type Equivalence struct {
+	Method string `json:"method"`
+	Equal  bool   `json:"equal"`
+}
+
+// DiffOp is one step in the alignment of two strings. Op is one of "equal",
+// "substitute", "delete" (only in A) or "insert" (only in B).
+type DiffOp struct {
+	Op         string   `json:"op"`
+	AOffset    int      `json:"a_offset"`
+	BOffset    int      `json:"b_offset"`
+	A          string   `json:"a,omitempty"`
+	B          string   `json:"b,omitempty"`
+	ACodePoint []string `json:"a_code_points,omitempty"`
+	BCodePoint []string `json:"b_code_points,omitempty"`
+}
+
+// equivalences lists the transformations Compare applies to both
+// strings before checking them for equality
+var equivalences = []struct {
+	method    string
+	transform func(string) string
+}{
+	{"NFC", norm.NFC.String},
+	{"NFKC", norm.NFKC.String},
+	{"case folding", func(s string) string { return cases.Fold().String(s) }},
+	{"NFKC + case folding", func(s string) string {
+		return norm.NFKC.String(cases.Fold().String(norm.NFD.String(s)))
+	}},
+	{"confusable skeleton", Skeleton},
+}
+
+// Compare aligns two strings at rune and grapheme level and checks
+// whether they are equivalent under each of the equivalences
+func Compare(a, b string) CompareData {
+	data := CompareData{
+		A:         a,
+		B:         b,
+		Identical: a == b,
+		Runes:     diffUnits(runeUnits(a), runeUnits(b)),
+		Graphemes: diffUnits(graphemeClusters(a), graphemeClusters(b)),
+	}
+	for _, e := range equivalences {
+		data.Equivalence = append(data.Equivalence, Equivalence{
+			Method: e.method,
+			Equal:  e.transform(a) == e.transform(b),
+		})
+	}
+	return data
+}
+
+// runeUnits splits a string into one string per rune. Each invalid UTF-8
+// sequence is a unit of its own, as it is a row of the rune table, so that
+// different invalid bytes do not align as equal.
+func runeUnits(s string) (units []string) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			size, _ = classifyInvalid(s[i:])
+		}
+		units = append(units, s[i:i+size])
+		i += size
+	}
+	return
+}
+
+// unitCodePoints returns the U+XXXX notation of each rune of a unit, and the
+// bytes of each invalid UTF-8 sequence in it
+func unitCodePoints(s string) (cps []string) {
+	for _, u := range runeUnits(s) {
+		if !utf8.ValidString(u) {
+			cps = append(cps, "invalid "+hex.EncodeToString([]byte(u)))
+			continue
+		}
+		cps = append(cps, CodePoints(u)...)
+	}
+	return
+}
+
+// diffUnits aligns two sequences using their longest common subsequence.
+// Within each gap between common units, removed and inserted units are paired
+// up as substitutions and any remainder is reported as a delete or insert.
+func diffUnits(a, b []string) []DiffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []DiffOp
+	var aOff, bOff int
+	var dels, ins []DiffOp
+	flush := func() {
+		for len(dels) > 0 && len(ins) > 0 {
+			ops = append(ops, DiffOp{
+				Op:         "substitute",
+				AOffset:    dels[0].AOffset,
+				BOffset:    ins[0].BOffset,
+				A:          dels[0].A,
+				B:          ins[0].B,
+				ACodePoint: dels[0].ACodePoint,
+				BCodePoint: ins[0].BCodePoint,
+			})
+			dels, ins = dels[1:], ins[1:]
+		}
+		ops = append(ops, dels...)
+		ops = append(ops, ins...)
+		dels, ins = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			ops = append(ops, DiffOp{
+				Op: "equal", AOffset: aOff, BOffset: bOff,
+				A: a[i], B: b[j], ACodePoint: unitCodePoints(a[i]), BCodePoint: unitCodePoints(b[j]),
+			})
+			aOff += len(a[i])
+			bOff += len(b[j])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			dels = append(dels, DiffOp{
+				Op: "delete", AOffset: aOff, BOffset: bOff,
+				A: a[i], ACodePoint: unitCodePoints(a[i]),
+			})
+			aOff += len(a[i])
+			i++
+		default:
+			ins = append(ins, DiffOp{
+				Op: "insert", AOffset: aOff, BOffset: bOff,
+				B: b[j], BCodePoint: unitCodePoints(b[j]),
+			})
+			bOff += len(b[j])
+			j++
+		}
+	}
+	flush()
+	return ops
+}
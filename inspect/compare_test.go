@@ -0,0 +1,89 @@
+package inspect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		wantOps []string
+	}{
+		{"identical", "abc", "abc", []string{"equal", "equal", "equal"}},
+		{"substitution", "pаypal", "paypal", []string{"equal", "substitute", "equal", "equal", "equal", "equal"}},
+		{"insertion", "ab", "a‍b", []string{"equal", "insert", "equal"}},
+		{"deletion", "a­b", "ab", []string{"equal", "delete", "equal"}},
+		{"decomposed vs precomposed", "piña", "piña", []string{"equal", "equal", "substitute", "delete", "equal"}},
+		{"empty", "", "ab", []string{"insert", "insert"}},
+		{"different invalid sequences", "a\xc0\xafb", "a\xed\xa0\xbdb", []string{"equal", "substitute", "equal"}},
+		{"same invalid sequence", "a\xffb", "a\xffb", []string{"equal", "equal", "equal"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ops := diffUnits(runeUnits(tc.a), runeUnits(tc.b))
+			var got []string
+			for _, op := range ops {
+				got = append(got, op.Op)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.wantOps, ",") {
+				t.Errorf("diffUnits(%q, %q) ops = %v, want %v", tc.a, tc.b, got, tc.wantOps)
+			}
+		})
+	}
+}
+
+func TestCompareStrings(t *testing.T) {
+	data := Compare("piñata", "piñata")
+
+	if data.Identical {
+		t.Errorf("expected strings not to be identical")
+	}
+	want := map[string]bool{
+		"NFC":                 true,
+		"NFKC":                true,
+		"case folding":        false,
+		"NFKC + case folding": true,
+		"confusable skeleton": true,
+	}
+	for _, e := range data.Equivalence {
+		if e.Equal != want[e.Method] {
+			t.Errorf("equal after %s = %v, want %v", e.Method, e.Equal, want[e.Method])
+		}
+	}
+
+	// the grapheme alignment pairs n+U+0303 with ñ as a single substitution
+	var subs []DiffOp
+	for _, op := range data.Graphemes {
+		if op.Op != "equal" {
+			subs = append(subs, op)
+		}
+	}
+	if len(subs) != 1 || subs[0].Op != "substitute" || subs[0].AOffset != 2 || subs[0].BOffset != 2 {
+		t.Fatalf("unexpected grapheme alignment: %+v", data.Graphemes)
+	}
+	if strings.Join(subs[0].ACodePoint, " ") != "U+006E U+0303" {
+		t.Errorf("unexpected code points for substituted grapheme: %v", subs[0].ACodePoint)
+	}
+
+	if !Compare("Straße", "STRASSE").Equivalence[2].Equal {
+		t.Errorf("expected Straße and STRASSE to be equal after case folding")
+	}
+}
+
+func TestCompareInvalidUTF8(t *testing.T) {
+	data := Compare("a\xc0\xaf", "a\xff")
+	if data.Identical {
+		t.Fatal("expected different invalid bytes not to be identical")
+	}
+	want := []DiffOp{
+		{Op: "equal", A: "a", B: "a", ACodePoint: []string{"U+0061"}, BCodePoint: []string{"U+0061"}},
+		{Op: "substitute", AOffset: 1, BOffset: 1, A: "\xc0\xaf", B: "\xff", ACodePoint: []string{"invalid c0af"}, BCodePoint: []string{"invalid ff"}},
+	}
+	if !reflect.DeepEqual(data.Runes, want) {
+		t.Errorf("Runes = %+v, want %+v", data.Runes, want)
+	}
+}
@@ -0,0 +1,172 @@
+package inspect
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed data/confusables.txt
+var confusablesTxt string
+
+var (
+	confusablesOnce sync.Once
+	confusables     map[rune]string
+)
+
+// ConfusableReport holds the UTS #39 skeleton of the input and the runes that
+// were replaced to produce it
+type ConfusableReport struct {
+	Skeleton  string           `json:"skeleton"`
+	ASCIILike bool             `json:"ascii_like"`
+	Mappings  []ConfusableRune `json:"mappings,omitempty"`
+}
+
+// ConfusableRune is an input rune that the skeleton replaces with a prototype
+type ConfusableRune struct {
+	Offset    int      `json:"offset"`
+	CodePoint string   `json:"code_point"`
+	Prototype []string `json:"prototype"`
+}
+
+// defaultIgnorable is the Default_Ignorable_Code_Point property, whose runes
+// are removed before computing a skeleton
+var defaultIgnorable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00AD, 0x00AD, 1},
+		{0x034F, 0x034F, 1},
+		{0x061C, 0x061C, 1},
+		{0x115F, 0x1160, 1},
+		{0x17B4, 0x17B5, 1},
+		{0x180B, 0x180F, 1},
+		{0x200B, 0x200F, 1},
+		{0x202A, 0x202E, 1},
+		{0x2060, 0x206F, 1},
+		{0x3164, 0x3164, 1},
+		{0xFE00, 0xFE0F, 1},
+		{0xFEFF, 0xFEFF, 1},
+		{0xFFA0, 0xFFA0, 1},
+		{0xFFF0, 0xFFF8, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1BCA0, 0x1BCA3, 1},
+		{0x1D173, 0x1D17A, 1},
+		{0xE0000, 0xE0FFF, 1},
+	},
+}
+
+// loadConfusables parses the embedded confusables.txt data on first use
+func loadConfusables() map[rune]string {
+	confusablesOnce.Do(func() {
+		m, err := parseConfusables(strings.NewReader(confusablesTxt))
+		if err != nil {
+			panic("wtutf: bad embedded confusables data: " + err.Error())
+		}
+		confusables = m
+	})
+	return confusables
+}
+
+// parseConfusables reads data in the confusables.txt format:
+// source ; prototype code points ; type
+func parseConfusables(r io.Reader) (map[rune]string, error) {
+	m := map[rune]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source, err := parseCodePoints(fields[0])
+		if err != nil || len(source) != 1 {
+			return nil, fmt.Errorf("bad source in line %q", scanner.Text())
+		}
+		proto, err := parseCodePoints(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		m[source[0]] = string(proto)
+	}
+	return m, scanner.Err()
+}
+
+// parseCodePoints parses a space separated list of hex code points
+func parseCodePoints(s string) ([]rune, error) {
+	var runes []rune
+	for _, f := range strings.Fields(s) {
+		v, err := strconv.ParseUint(f, 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		runes = append(runes, rune(v))
+	}
+	return runes, nil
+}
+
+// Skeleton implements the UTS #39 skeleton function: the input is converted
+// to NFD, default ignorable runes are removed, every rune is replaced with its
+// confusable prototype and the result is converted to NFD again. Two strings
+// with the same skeleton are visually confusable.
+func Skeleton(s string) string {
+	protos := loadConfusables()
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(defaultIgnorable, r) {
+			continue
+		}
+		if p, ok := protos[r]; ok {
+			b.WriteString(p)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFD.String(b.String())
+}
+
+// Confusables computes the skeleton of a string and lists the non-ASCII
+// runes that do not map to themselves. ASCIILike is set when a string containing
+// non-ASCII runes has an all-ASCII skeleton, i.e. it can pass for ASCII text.
+func Confusables(s string) *ConfusableReport {
+	report := &ConfusableReport{
+		Skeleton:  Skeleton(s),
+		ASCIILike: !isASCII(s),
+	}
+	if report.ASCIILike {
+		report.ASCIILike = isASCII(report.Skeleton)
+	}
+	for i, r := range s {
+		if r < utf8.RuneSelf {
+			// ASCII look-alikes such as 1 and l are reflected in the
+			// skeleton but not listed
+			continue
+		}
+		own := string(r)
+		if sk := Skeleton(own); sk != norm.NFD.String(own) {
+			report.Mappings = append(report.Mappings, ConfusableRune{
+				Offset:    i,
+				CodePoint: fmt.Sprintf("%U", r),
+				Prototype: CodePoints(sk),
+			})
+		}
+	}
+	return report
+}
+
+// isASCII reports whether s contains only ASCII bytes
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package inspect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		equal bool
+	}{
+		{"whole-script Cyrillic spoof", "аррӏе", "apple", true},
+		{"mixed-script spoof", "www.ցooցlе.com", "www.google.com", true},
+		{"digit one and letter l", "paypa1", "paypal", true},
+		{"rn and m", "rnicrosoft", "microsoft", true},
+		{"default ignorable removed", "pay​pal", "paypal", true},
+		{"different strings", "apple", "apples", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Skeleton(tc.a) == Skeleton(tc.b); got != tc.equal {
+				t.Errorf("Skeleton(%q) = %q, Skeleton(%q) = %q, want equal=%v", tc.a, Skeleton(tc.a), tc.b, Skeleton(tc.b), tc.equal)
+			}
+		})
+	}
+}
+
+func TestParseConfusables(t *testing.T) {
+	input := "# comment\n\n0430 ;\t0061 ;\tMA\t# ( а → a )\n00E6 ;\t0061 0065 ;\tMA\n"
+	m, err := parseConfusables(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseConfusables() error = %v", err)
+	}
+	if m['а'] != "a" || m['æ'] != "ae" || len(m) != 2 {
+		t.Errorf("unexpected mappings: %q", m)
+	}
+
+	if _, err := parseConfusables(strings.NewReader("zzzz ; 0061 ; MA\n")); err == nil {
+		t.Errorf("expected an error for a bad source code point")
+	}
+}
+
+func TestConfusableReport(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantASCII    bool
+		wantMappings int
+	}{
+		{"whole-script spoof", "аррӏе", true, 5},
+		{"plain ASCII", "apple", false, 0},
+		{"Japanese", "こんにちは", false, 0},
+		{"partly confusable", "арр日本", false, 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := Confusables(tc.input)
+			if report.ASCIILike != tc.wantASCII {
+				t.Errorf("ASCIILike = %v, want %v (skeleton %q)", report.ASCIILike, tc.wantASCII, report.Skeleton)
+			}
+			if len(report.Mappings) != tc.wantMappings {
+				t.Errorf("got %d mappings, want %d: %+v", len(report.Mappings), tc.wantMappings, report.Mappings)
+			}
+		})
+	}
+}
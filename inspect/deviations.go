@@ -0,0 +1,78 @@
+package inspect
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// deviationMappings are the UTS #46 deviation characters and what
+// transitional processing maps them to. Nontransitional processing keeps them,
+// so a name containing one has two different A-labels.
+var deviationMappings = map[rune]string{
+	'ß':      "ss", // LATIN SMALL LETTER SHARP S
+	'ς':      "σ",  // GREEK SMALL LETTER FINAL SIGMA
+	'\u200C': "",   // ZERO WIDTH NON-JOINER
+	'\u200D': "",   // ZERO WIDTH JOINER
+}
+
+// DeviationReport lists the deviation characters in the input and the
+// punycode it converts to under transitional (IDNA2003 compatible) and
+// nontransitional processing. Differ is set when the two disagree, in which
+// case older and newer resolvers look up different names.
+type DeviationReport struct {
+	Characters           []DeviationCharacter `json:"characters"`
+	Transitional         string               `json:"transitional,omitempty"`
+	TransitionalError    string               `json:"transitional_error,omitempty"`
+	NonTransitional      string               `json:"nontransitional,omitempty"`
+	NonTransitionalError string               `json:"nontransitional_error,omitempty"`
+	Differ               bool                 `json:"differ"`
+}
+
+// DeviationCharacter is a deviation character and its transitional mapping
+type DeviationCharacter struct {
+	Offset       int    `json:"offset"`
+	CodePoint    string `json:"code_point"`
+	Name         string `json:"name"`
+	Transitional string `json:"transitional"`
+}
+
+// Deviations lists the deviation characters in s and converts it with
+// transitional and nontransitional processing. It returns nil when s has no
+// deviation characters. Both conversions map the input as UTS #46 lookup
+// does, since transitional processing is part of the mapping step.
+func (a *Analyzer) Deviations(s string) *DeviationReport {
+	strict := a.opts.Strict
+	report := &DeviationReport{}
+	for i, r := range s {
+		if r == utf8.RuneError {
+			continue
+		}
+		if mapped, ok := deviationMappings[r]; ok {
+			report.Characters = append(report.Characters, DeviationCharacter{
+				Offset:       i,
+				CodePoint:    fmt.Sprintf("%U", r),
+				Name:         RuneName(r),
+				Transitional: mapped,
+			})
+		}
+	}
+	if report.Characters == nil {
+		return nil
+	}
+
+	rules := ConversionRules(strict, false)
+	if punycode, err := toPuny(s, slices.Concat(rules, mappingRules(strict, true))); err == nil {
+		report.Transitional = punycode
+	} else {
+		report.TransitionalError = err.Error()
+	}
+	if punycode, err := toPuny(s, slices.Concat(rules, mappingRules(strict, false))); err == nil {
+		report.NonTransitional = punycode
+	} else {
+		report.NonTransitionalError = err.Error()
+	}
+	report.Differ = report.Transitional != report.NonTransitional ||
+		report.TransitionalError != report.NonTransitionalError
+	return report
+}
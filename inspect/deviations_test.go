@@ -0,0 +1,55 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeviationReport(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		transitional    string
+		nonTransitional string
+		mapped          []string
+	}{
+		{"sharp s", "faß.de", "fass.de", "xn--fa-hia.de", []string{"ss"}},
+		{"final sigma", "ς.gr", "xn--4xa.gr", "xn--3xa.gr", []string{"σ"}},
+		{"zero width joiner", "\u0915\u094d\u200d\u0937", "xn--11b2ezc", "xn--11b2ezcw70k", []string{""}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := NewAnalyzer(Options{}).Deviations(tc.input)
+			if report == nil {
+				t.Fatalf("expected a deviation report for %q", tc.input)
+			}
+			if report.Transitional != tc.transitional || report.NonTransitional != tc.nonTransitional || !report.Differ {
+				t.Errorf("Deviations(%q) = %+v, want %s and %s", tc.input, report, tc.transitional, tc.nonTransitional)
+			}
+			var mapped []string
+			for _, c := range report.Characters {
+				mapped = append(mapped, c.Transitional)
+			}
+			if !reflect.DeepEqual(mapped, tc.mapped) {
+				t.Errorf("transitional mappings = %q, want %q", mapped, tc.mapped)
+			}
+		})
+	}
+}
+
+func TestDeviationReportNone(t *testing.T) {
+	if report := NewAnalyzer(Options{}).Deviations("example.com"); report != nil {
+		t.Errorf("expected no deviation report, got %+v", report)
+	}
+}
+
+func TestTransitionalConversion(t *testing.T) {
+	data := NewAnalyzer(Options{Transitional: true}).Analyze("Faß.de")
+	if data.Punycode != "fass.de" {
+		t.Errorf("transitional punycode = %q (%s), want fass.de", data.Punycode, data.PunycodeError)
+	}
+	if data.Deviations == nil || !data.Deviations.Differ {
+		t.Errorf("expected differing deviations, got %+v", data.Deviations)
+	}
+}
@@ -1,47 +1,33 @@
-package cmd
+package inspect
 
 import (
-	"fmt"
-	"io"
+	"strings"
 	"unicode"
 )
 
-// listRanges takes a string and returns a map of Unicode range
-// names and the count of runes within that range
-func listRanges(ustring string) map[string]int {
+// ListRanges takes a string and returns a map of Unicode range
+// names and the count of runes within that range. Runes used by more than one
+// script, according to their Script_Extensions, count towards each of them.
+func ListRanges(ustring string) map[string]int {
 	rangeCounts := map[string]int{}
 
 	for _, r := range ustring {
-		rangeCounts[FindRange(r)]++
+		for _, name := range FindRange(r) {
+			rangeCounts[name]++
+		}
 	}
 	return rangeCounts
 }
 
-// checkMultipleRange takes a string and determines whether the string
-// contains characters from more than one range.
-// Runes from the 'Common' range are ignored.
-func checkMultipleRange(w io.Writer, showRanges bool, ustring string) (multiRange bool) {
-	var ranges int
-	var out string
-	for i, count := range listRanges(ustring) {
-		if i == "Common" {
-			continue
-		}
-		ranges++
-		if showRanges {
-			out += fmt.Sprintf("%s: %d\n", i, count)
-		}
-	}
-	if ranges > 1 {
-		multiRange = true
-		if w != nil && out != "" {
-			fmt.Fprint(w, out)
-		}
-	}
-	return
+// FindRange returns the Unicode scripts a rune is used with, from its
+// Script_Extensions property
+func FindRange(r rune) []string {
+	return scriptExtensions(r)
 }
 
-func FindRange(r rune) (rangename string) {
+// findScript returns the name of the rune's Script property, or "" if the
+// rune is not assigned to a script
+func findScript(r rune) (rangename string) {
 	for i, name := range unicode.Scripts {
 		if unicode.Is(name, r) {
 			rangename = i
@@ -50,10 +36,10 @@ func FindRange(r rune) (rangename string) {
 	return
 }
 
-// politePrint takes a rune and outputs a string safe to print in the terminal,
+// PolitePrint takes a rune and outputs a string safe to print in the terminal,
 // and where possible with the original character. Control and formatting
 // characters are filtered, some combining characters are printed.
-func politePrint(r rune) string {
+func PolitePrint(r rune) string {
 	switch {
 	// render combining diacritics with one or two ◌ dotted circle characters
 	// to keep them from being printed over the colon
@@ -63,7 +49,7 @@ func politePrint(r rune) string {
 			return " " + string(r)
 		}
 		if 0x035C <= r && r <= 0x0362 {
-			// combining diacritical marks, 2 characters
+			// combining diacritical Marks, 2 characters
 			return "◌" + string(r) + "◌"
 		}
 		return " ◌" + string(r)
@@ -97,3 +83,13 @@ func politePrint(r rune) string {
 	}
 	return " " + string(r)
 }
+
+// PoliteString applies PolitePrint to every rune in a string, without the
+// padding PolitePrint adds for table output
+func PoliteString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteString(strings.TrimPrefix(PolitePrint(r), " "))
+	}
+	return b.String()
+}
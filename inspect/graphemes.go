@@ -0,0 +1,63 @@
+package inspect
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// graphemeClusters splits a string into extended grapheme clusters (UAX #29),
+// the units a reader perceives as single characters
+func graphemeClusters(s string) (clusters []string) {
+	state := -1
+	for s != "" {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, cluster)
+	}
+	return
+}
+
+// GraphemeCluster is one user-perceived character of the input. Joiner is set
+// when the cluster contains a zero width joiner or non-joiner, and
+// VariationSelector when it contains a variation selector.
+type GraphemeCluster struct {
+	Offset            int      `json:"offset"`
+	Length            int      `json:"length"`
+	CodePoints        []string `json:"code_points"`
+	Joiner            bool     `json:"joiner,omitempty"`
+	VariationSelector bool     `json:"variation_selector,omitempty"`
+}
+
+// Graphemes segments a string into grapheme clusters and marks the
+// clusters that were built with joiners or variation selectors
+func Graphemes(s string) (report []GraphemeCluster) {
+	offset := 0
+	for _, cluster := range graphemeClusters(s) {
+		gc := GraphemeCluster{
+			Offset:     offset,
+			Length:     len(cluster),
+			CodePoints: CodePoints(cluster),
+		}
+		for _, r := range cluster {
+			gc.Joiner = gc.Joiner || unicode.Is(unicode.Join_Control, r)
+			gc.VariationSelector = gc.VariationSelector || unicode.Is(unicode.Variation_Selector, r)
+		}
+		report = append(report, gc)
+		offset += len(cluster)
+	}
+	return
+}
+
+// Marks describes what a cluster was built with, for plain text output
+func (gc GraphemeCluster) Marks() string {
+	var marks []string
+	if gc.Joiner {
+		marks = append(marks, "joiner")
+	}
+	if gc.VariationSelector {
+		marks = append(marks, "variation selector")
+	}
+	return strings.Join(marks, ", ")
+}
@@ -0,0 +1,19 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemeReport(t *testing.T) {
+	got := Graphemes("a👨‍👩‍👧☺️é")
+	want := []GraphemeCluster{
+		{Offset: 0, Length: 1, CodePoints: []string{"U+0061"}},
+		{Offset: 1, Length: 18, CodePoints: []string{"U+1F468", "U+200D", "U+1F469", "U+200D", "U+1F467"}, Joiner: true},
+		{Offset: 19, Length: 6, CodePoints: []string{"U+263A", "U+FE0F"}, VariationSelector: true},
+		{Offset: 25, Length: 3, CodePoints: []string{"U+0065", "U+0301"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Graphemes() = %+v, want %+v", got, want)
+	}
+}
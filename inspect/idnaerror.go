@@ -0,0 +1,196 @@
+package inspect
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// IDNAError classifies an error returned by the idna package. Code is a
+// stable wtutf error code that scripts can branch on, IDNACode is the UTS #46
+// error code of the check that failed, and Reference cites the section of
+// the standard that the input violates.
+type IDNAError struct {
+	Message     string `json:"message"`
+	Code        string `json:"code"`
+	IDNACode    string `json:"idna_code,omitempty"`
+	Explanation string `json:"explanation"`
+	Reference   string `json:"reference"`
+}
+
+type idnaErrorClass struct {
+	code, explanation, reference string
+}
+
+// idnaErrorClasses maps the UTS #46 error codes used by the idna package to
+// wtutf error codes. The length error, A4, is refined by ClassifyIDNAError.
+var idnaErrorClasses = map[string]idnaErrorClass{
+	"V1": {"not-normalized", "a label is not in Unicode Normalization Form C", "UTS #46 section 4.1, validity criterion 1"},
+	"V2": {"hyphen", "a label has hyphens in the third and fourth positions, which are reserved for prefixes like xn--", "UTS #46 section 4.1, validity criterion 2; RFC 5891 section 4.2.3.1"},
+	"V3": {"hyphen", "a label begins or ends with a hyphen", "UTS #46 section 4.1, validity criterion 3; RFC 5891 section 4.2.3.1"},
+	"V6": {"leading-combining-mark", "a label begins with a combining mark", "UTS #46 section 4.1, validity criterion 6; RFC 5891 section 4.2.3.2"},
+	"V7": {"disallowed-rune", "a label contains a code point that is not valid in domain names", "UTS #46 section 4.1, validity criterion 7; RFC 5892"},
+	"P1": {"disallowed-rune", "a code point is disallowed by the IDNA mapping table", "UTS #46 section 4, processing step 1; RFC 5892"},
+	"U1": {"std3", "a label contains an ASCII character other than a letter, digit or hyphen", "UTS #46 section 4, UseSTD3ASCIIRules; RFC 1034 section 3.5"},
+	"P4": {"punycode", "an xn-- label is not valid Punycode, or decodes to plain ASCII", "UTS #46 section 4, processing step 4; RFC 3492"},
+	"A3": {"punycode", "a label could not be encoded as Punycode", "UTS #46 section 4.2, ToASCII step 2; RFC 3492"},
+	"A4": {"label-length", "a label or the whole name is too long or empty", "UTS #46 section 4.2, ToASCII step 4; RFC 1035 section 2.3.4"},
+	"B":  {"bidi", "a right-to-left label breaks the Bidi Rule", "RFC 5893 section 2"},
+	"C":  {"joiner", "a zero width joiner or non-joiner is not in a context that allows it", "UTS #46 section 4.1, validity criterion 8; RFC 5892 appendix A"},
+}
+
+// ClassifyIDNAError maps an error from converting s with idna.ToASCII or
+// idna.ToUnicode to an IDNAError. The idna package does not export its error
+// codes, so the checks it makes are run again on s one at a time, in the
+// order the package makes them, and the first check that fails with the same
+// error names it.
+func ClassifyIDNAError(s string, err error) *IDNAError {
+	if err == nil {
+		return nil
+	}
+	e := &IDNAError{Message: err.Error(), Code: "unknown"}
+	code := reproduceIDNAError(s, err)
+	class, ok := idnaErrorClasses[code]
+	if !ok {
+		return e
+	}
+	e.IDNACode = code
+	e.Code, e.Explanation, e.Reference = class.code, class.explanation, class.reference
+	if code == "A4" {
+		// the lengths are of the A-labels, which the Punycode profile
+		// encodes without checking anything
+		name, _ := idna.Punycode.ToASCII(s)
+		e.Code, e.Explanation = classifyLengthError(name)
+	}
+	return e
+}
+
+// idnaCheck is one of the checks the idna package makes, and refines the UTS
+// #46 error code it reports from the string and the error it failed with. An
+// empty code means the failure does not belong to the check. Every profile
+// encodes the labels it has checked, so the checks made before encoding are
+// tried with ToUnicode, which does not encode.
+type idnaCheck struct {
+	rules   []idna.Option
+	toASCII bool
+	code    func(s string, err error) string
+}
+
+// idnaChecks are the checks of the idna package in the order it makes them:
+// decoding xn-- labels, mapping, validating each label, the Bidi Rule, the
+// DNS length limits and encoding labels as Punycode. The code func of each
+// returns the UTS #46 error code, or "" when the error is not one the check
+// reports.
+var idnaChecks = []idnaCheck{
+	{nil, false, func(string, error) string { return "P4" }},
+	// ASCII the STD3 rules disallow is disallowed by the registration
+	// mapping too, and the narrower reason is the one reported
+	{[]idna.Option{idna.ValidateLabels(true), idna.StrictDomainName(true)}, false, func(_ string, err error) string {
+		if isRuneError(err) {
+			return "U1"
+		}
+		return ""
+	}},
+	{[]idna.Option{idna.ValidateForRegistration()}, false, func(s string, err error) string {
+		// only the errors of the registration mapping; the checks that
+		// follow it are tried on their own below
+		switch {
+		case isRuneError(err):
+			return "P1"
+		case !norm.NFC.IsNormalString(s):
+			return "V1"
+		}
+		return ""
+	}},
+	{[]idna.Option{idna.CheckHyphens(true)}, false, func(s string, _ error) string {
+		for _, label := range splitLabels(s) {
+			if len(label.text) >= 4 && label.text[2:4] == "--" {
+				return "V2"
+			}
+		}
+		return "V3"
+	}},
+	{[]idna.Option{idna.CheckJoiners(true)}, false, func(s string, _ error) string {
+		for _, label := range splitLabels(s) {
+			if r, _ := utf8.DecodeRuneInString(label.text); unicode.Is(unicode.M, r) {
+				return "V6"
+			}
+		}
+		return "C"
+	}},
+	{[]idna.Option{idna.ValidateLabels(true)}, false, func(s string, err error) string {
+		// a decoded xn-- label must be in NFC as well
+		u, _ := idna.Punycode.ToUnicode(s)
+		switch {
+		case isRuneError(err):
+			return "P1"
+		case !norm.NFC.IsNormalString(u):
+			return "V1"
+		}
+		return "V7"
+	}},
+	{[]idna.Option{idna.BidiRule()}, false, func(string, error) string { return "B" }},
+	{[]idna.Option{idna.VerifyDNSLength(true)}, true, func(s string, _ error) string {
+		// labels that cannot be encoded have no length
+		if _, err := idna.Punycode.ToASCII(s); err != nil {
+			return ""
+		}
+		return "A4"
+	}},
+	{nil, true, func(string, error) string { return "A3" }},
+}
+
+// idnaMappings are the mappings a profile can apply before its checks. The
+// errors name the mapped labels, so each check is tried under each mapping.
+var idnaMappings = [][]idna.Option{
+	nil,
+	{idna.MapForLookup(), idna.ValidateLabels(false), idna.StrictDomainName(false)},
+	{idna.MapForLookup(), idna.ValidateLabels(false), idna.StrictDomainName(false), idna.Transitional(true)},
+}
+
+// reproduceIDNAError returns the UTS #46 code of the first of the idnaChecks
+// that fails on s with err
+func reproduceIDNAError(s string, err error) string {
+	for _, check := range idnaChecks {
+		for _, mapping := range idnaMappings {
+			p := idna.New(append(slices.Clip(mapping), check.rules...)...)
+			convert := p.ToUnicode
+			if check.toASCII {
+				convert = p.ToASCII
+			}
+			_, perr := convert(s)
+			if perr == nil || perr.Error() != err.Error() {
+				continue
+			}
+			if code := check.code(s, perr); code != "" {
+				return code
+			}
+		}
+	}
+	return ""
+}
+
+// isRuneError reports whether err is about a single code point rather than
+// a label
+func isRuneError(err error) bool {
+	return strings.HasPrefix(err.Error(), "idna: disallowed rune")
+}
+
+// classifyLengthError tells apart the DNS length errors the idna package
+// reports with a single code, from the label or name it reports them for
+func classifyLengthError(s string) (code, explanation string) {
+	name := strings.TrimSuffix(s, ".")
+	if len(name) > 253 {
+		return "domain-too-long", "the domain name is longer than 253 octets"
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) > maxLabelLength {
+			return "label-too-long", "a label is longer than 63 octets"
+		}
+	}
+	return "empty-label", "the domain name or one of its labels is empty"
+}
@@ -0,0 +1,96 @@
+package inspect
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/idna"
+)
+
+func TestClassifyIDNAError(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		strict   bool
+		code     string
+		idnaCode string
+	}{
+		{"not NFC", "pin\u0303ata", true, "not-normalized", "V1"},
+		{"hyphens in positions 3 and 4", "ab--c", false, "hyphen", "V2"},
+		{"leading hyphen", "-bad", false, "hyphen", "V3"},
+		{"leading combining mark", "\u0301a", false, "leading-combining-mark", "V6"},
+		{"std3", "a_b", true, "std3", "U1"},
+		{"disallowed rune", "a\u2028b", true, "disallowed-rune", "P1"},
+		{"bad punycode", "xn--abc-", false, "punycode", "P4"},
+		{"invalid UTF-8", "a\xc0\xafb", false, "disallowed-rune", "V7"},
+		{"empty label", "a..b", true, "empty-label", "A4"},
+		{"label too long", strings.Repeat("a", 64) + ".com", true, "label-too-long", "A4"},
+		{"name too long", strings.Repeat("abcdefghij.", 25) + "com", true, "domain-too-long", "A4"},
+		{"bidi rule", "اb", false, "bidi", "B"},
+		{"joiner", "a\u200db", false, "joiner", "C"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := toPuny(tc.input, ConversionRules(tc.strict, false))
+			if err == nil {
+				t.Fatalf("expected %q to fail conversion", tc.input)
+			}
+			got := ClassifyIDNAError(tc.input, err)
+			if got.Code != tc.code || got.IDNACode != tc.idnaCode {
+				t.Errorf("ClassifyIDNAError(%v) = %s (%s), want %s (%s)", err, got.Code, got.IDNACode, tc.code, tc.idnaCode)
+			}
+			if got.Message != err.Error() || got.Explanation == "" || got.Reference == "" {
+				t.Errorf("ClassifyIDNAError(%v) = %+v, want message, explanation and reference", err, got)
+			}
+		})
+	}
+}
+
+func TestClassifyIDNAErrorProfiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile *idna.Profile
+		input   string
+		code    string
+	}{
+		{"std3 after lookup mapping", idna.Lookup, "a_b.example", "std3"},
+		{"hyphen in a mapped label", idna.Lookup, "AB-.example", "hyphen"},
+		{"joiner in a mapped label", idna.Lookup, "A\u200dB", "joiner"},
+		{"disallowed in registration", idna.Registration, "Example", "disallowed-rune"},
+		{"bad punycode", idna.Punycode, "xn--abc-", "punycode"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.profile.ToASCII(tc.input)
+			if err == nil {
+				t.Fatalf("expected %q to fail conversion", tc.input)
+			}
+			if got := ClassifyIDNAError(tc.input, err); got.Code != tc.code {
+				t.Errorf("ClassifyIDNAError(%q, %v) = %s, want %s", tc.input, err, got.Code, tc.code)
+			}
+		})
+	}
+}
+
+func TestClassifyIDNAErrorUnknown(t *testing.T) {
+	if ClassifyIDNAError("", nil) != nil {
+		t.Error("expected nil for a nil error")
+	}
+	got := ClassifyIDNAError("a", errors.New("something else"))
+	if got.Code != "unknown" || got.IDNACode != "" || got.Message != "something else" {
+		t.Errorf("unexpected classification of a foreign error: %+v", got)
+	}
+}
+
+func TestAnalyzeIDNAError(t *testing.T) {
+	data := NewAnalyzer(Options{FromPunycode: true}).Analyze("xn--abc-")
+	if !strings.HasPrefix(data.PunycodeError, "could not decode punycode input: idna: ") {
+		t.Errorf("PunycodeError = %q, want the underlying error", data.PunycodeError)
+	}
+	if data.IDNAError == nil || data.IDNAError.Code != "punycode" {
+		t.Errorf("IDNAError = %+v, want code punycode", data.IDNAError)
+	}
+}
@@ -0,0 +1,74 @@
+package inspect
+
+import (
+	"cmp"
+
+	"golang.org/x/net/idna"
+)
+
+// maxLabelLength is the longest a DNS label may be, in octets (RFC 1035)
+const maxLabelLength = 63
+
+// LabelReport is the IDNA conversion of one label of a domain name. Length
+// is the A-label's length in octets, which is what the DNS limits.
+type LabelReport struct {
+	Offset      int      `json:"offset"`
+	Label       string   `json:"label"`
+	ALabel      string   `json:"a_label,omitempty"`
+	ULabel      string   `json:"u_label,omitempty"`
+	Length      int      `json:"length"`
+	Error       string   `json:"error,omitempty"`
+	ErrorCode   string   `json:"error_code,omitempty"`
+	FailedRules []string `json:"failed_rules,omitempty"`
+}
+
+// isLabelSeparator reports whether r separates domain labels: the full stop
+// and the ideographic, fullwidth and halfwidth full stops UTS #46 maps to it
+func isLabelSeparator(r rune) bool {
+	return r == '.' || r == '\u3002' || r == '\uFF0E' || r == '\uFF61'
+}
+
+// Labels splits a domain name into labels and converts each one to its
+// A-label and U-label separately, so a failure can be traced to the label
+// that caused it and the rules it breaks. A trailing separator, for the root
+// label of a fully qualified name, is not reported as an empty label.
+func (a *Analyzer) Labels(s string) (labels []LabelReport) {
+	for _, label := range splitLabels(s) {
+		labels = append(labels, labelReport(label.text, label.offset, a.rules))
+	}
+	return
+}
+
+// labelReport converts a single label
+func labelReport(label string, offset int, rules []idna.Option) LabelReport {
+	report := LabelReport{
+		Offset:      offset,
+		Label:       label,
+		FailedRules: FailedRules(label),
+	}
+	alabel, aerr := toPuny(label, rules)
+	ulabel, uerr := fromPuny(label, rules)
+	if aerr == nil {
+		report.ALabel = alabel
+	}
+	if uerr == nil {
+		report.ULabel = ulabel
+	}
+	if err := cmp.Or(aerr, uerr); err != nil {
+		report.Error = err.Error()
+		report.ErrorCode = ClassifyIDNAError(label, err).Code
+	}
+
+	// a failed conversion can still return a partly converted label
+	report.Length = len(label)
+	if aerr == nil {
+		report.Length = len(alabel)
+	}
+	switch {
+	case label == "":
+		report.FailedRules = append(report.FailedRules, "empty label (RFC 1035)")
+	case report.Length > maxLabelLength:
+		report.FailedRules = append(report.FailedRules, "label longer than 63 octets (RFC 1035)")
+	}
+	return report
+}
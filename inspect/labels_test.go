@@ -0,0 +1,83 @@
+package inspect
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLabelReports(t *testing.T) {
+	labels := NewAnalyzer(Options{}).Labels("www.ցooցlе.com")
+	if len(labels) != 3 {
+		t.Fatalf("expected 3 labels, got %+v", labels)
+	}
+	want := LabelReport{Offset: 4, Label: "ցooցlе", ALabel: "xn--ool-tdd07nca", ULabel: "ցooցlе", Length: 16}
+	if !reflect.DeepEqual(labels[1], want) {
+		t.Errorf("label 2 = %+v, want %+v", labels[1], want)
+	}
+}
+
+func TestLabelReportsSeparators(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"trailing root label", "example.com.", []string{"example", "com"}},
+		{"ideographic full stop", "例え。jp", []string{"例え", "jp"}},
+		{"fullwidth and halfwidth full stops", "a．b｡c", []string{"a", "b", "c"}},
+		{"empty label", "a..b", []string{"a", "", "b"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, l := range NewAnalyzer(Options{}).Labels(tc.input) {
+				got = append(got, l.Label)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("labels of %q = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLabelReportLengthOfFailedLabel(t *testing.T) {
+	// the failed conversion returns "xn----rga", which is not the label's
+	// A-label, so the length is that of the label
+	l := labelReport("-ñ", 0, ConversionRules(false, false))
+	if l.Error == "" || l.ALabel != "" {
+		t.Fatalf("expected a failed conversion, got %+v", l)
+	}
+	if l.Length != len("-ñ") {
+		t.Errorf("Length = %d, want %d", l.Length, len("-ñ"))
+	}
+}
+
+func TestLabelReportFailures(t *testing.T) {
+	tests := []struct {
+		name      string
+		label     string
+		wantError bool
+		wantRule  string
+	}{
+		{"leading hyphen", "-bad", true, "CheckHyphens (UTS 46)"},
+		{"64 octet label", strings.Repeat("a", 64), false, "label longer than 63 octets (RFC 1035)"},
+		{"A-label over 63 octets", strings.Repeat("é", 60), false, "label longer than 63 octets (RFC 1035)"},
+		{"empty label", "", false, "empty label (RFC 1035)"},
+		{"joiner", "a‍b", true, "CheckJoiners (RFC 5892)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			l := labelReport(tc.label, 0, ConversionRules(false, false))
+			if (l.Error != "") != tc.wantError {
+				t.Errorf("Error = %q, wantError %t", l.Error, tc.wantError)
+			}
+			if !slices.Contains(l.FailedRules, tc.wantRule) {
+				t.Errorf("FailedRules = %q, want it to contain %q", l.FailedRules, tc.wantRule)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package inspect
+
+import (
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalForm holds the input converted to one Unicode normalization form
+type NormalForm struct {
+	Form     string       `json:"form"`
+	Output   string       `json:"output"`
+	IsNormal bool         `json:"is_normal"`
+	Changes  []NormChange `json:"changes,omitempty"`
+}
+
+// NormChange is a run of input runes that a normalization form rewrites
+type NormChange struct {
+	Offset int      `json:"offset"`
+	From   []string `json:"from"`
+	To     []string `json:"to"`
+}
+
+var normalForms = []struct {
+	name string
+	form norm.Form
+}{
+	{"NFC", norm.NFC},
+	{"NFD", norm.NFD},
+	{"NFKC", norm.NFKC},
+	{"NFKD", norm.NFKD},
+}
+
+// NormalizationForms takes a string and returns it under each of the four
+// Unicode normalization forms, along with the runs of runes that change
+func NormalizationForms(s string) []NormalForm {
+	var forms []NormalForm
+	for _, nf := range normalForms {
+		forms = append(forms, NormalForm{
+			Form:     nf.name,
+			Output:   nf.form.String(s),
+			IsNormal: nf.form.IsNormalString(s),
+			Changes:  normChanges(nf.form, s),
+		})
+	}
+	return forms
+}
+
+// normChanges splits s at normalization boundaries and reports every segment
+// that is rewritten by the form f. Segments between boundaries normalize
+// independently, so each change lines up with a run of the original input.
+func normChanges(f norm.Form, s string) (changes []NormChange) {
+	for i := 0; i < len(s); {
+		n := f.NextBoundaryInString(s[i:], true)
+		if n <= 0 {
+			n = len(s) - i
+		}
+		seg := s[i : i+n]
+		if out := f.String(seg); out != seg {
+			changes = append(changes, NormChange{
+				Offset: i,
+				From:   CodePoints(seg),
+				To:     CodePoints(out),
+			})
+		}
+		i += n
+	}
+	return
+}
+
+// CodePoints returns the U+XXXX notation of each rune in s
+func CodePoints(s string) []string {
+	var cps []string
+	for _, r := range s {
+		cps = append(cps, fmt.Sprintf("%U", r))
+	}
+	return cps
+}
@@ -0,0 +1,68 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizationForms(t *testing.T) {
+	decomposed := "piñata"
+	precomposed := "piñata"
+
+	tests := []struct {
+		name       string
+		input      string
+		wantNormal map[string]bool
+		wantOutput map[string]string
+		wantChange map[string][]NormChange
+	}{
+		{
+			name:       "decomposed piñata",
+			input:      decomposed,
+			wantNormal: map[string]bool{"NFC": false, "NFD": true, "NFKC": false, "NFKD": true},
+			wantOutput: map[string]string{"NFC": precomposed, "NFD": decomposed},
+			wantChange: map[string][]NormChange{
+				"NFC": {{Offset: 2, From: []string{"U+006E", "U+0303"}, To: []string{"U+00F1"}}},
+				"NFD": nil,
+			},
+		},
+		{
+			name:       "precomposed piñata",
+			input:      precomposed,
+			wantNormal: map[string]bool{"NFC": true, "NFD": false, "NFKC": true, "NFKD": false},
+			wantOutput: map[string]string{"NFD": decomposed},
+			wantChange: map[string][]NormChange{
+				"NFD": {{Offset: 2, From: []string{"U+00F1"}, To: []string{"U+006E", "U+0303"}}},
+			},
+		},
+		{
+			name:       "compatibility ligature",
+			input:      "ﬁx",
+			wantNormal: map[string]bool{"NFC": true, "NFD": true, "NFKC": false, "NFKD": false},
+			wantOutput: map[string]string{"NFKC": "fix"},
+			wantChange: map[string][]NormChange{
+				"NFKC": {{Offset: 0, From: []string{"U+FB01"}, To: []string{"U+0066", "U+0069"}}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			forms := NormalizationForms(tc.input)
+			if len(forms) != 4 {
+				t.Fatalf("expected 4 forms, got %d", len(forms))
+			}
+			for _, f := range forms {
+				if want, ok := tc.wantNormal[f.Form]; ok && f.IsNormal != want {
+					t.Errorf("%s IsNormal = %v, want %v", f.Form, f.IsNormal, want)
+				}
+				if want, ok := tc.wantOutput[f.Form]; ok && f.Output != want {
+					t.Errorf("%s Output = %q, want %q", f.Form, f.Output, want)
+				}
+				if want, ok := tc.wantChange[f.Form]; ok && !reflect.DeepEqual(f.Changes, want) {
+					t.Errorf("%s Changes = %+v, want %+v", f.Form, f.Changes, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package inspect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy declares which characters and scripts a kind of string may use, for
+// example usernames or display names. Rules that are left empty are not
+// checked. A policy file is the YAML form of a Policy:
+//
+//	name: usernames
+//	scripts: [Latin]
+//	categories: [L, Nd, Pc]
+//	max_bytes: 32
+//	normalization: NFC
+//	forbidden: [U+0130, U+0131]
+type Policy struct {
+	Name string `yaml:"name" json:"name,omitempty"`
+	// Scripts are the allowed scripts. Characters of the Common and
+	// Inherited scripts, such as digits and combining marks, are always
+	// allowed, and a character used with several scripts is allowed when
+	// any of them is.
+	Scripts []string `yaml:"scripts" json:"scripts,omitempty"`
+	// Blocks are the allowed Unicode blocks, such as "Basic Latin"
+	Blocks []string `yaml:"blocks" json:"blocks,omitempty"`
+	// Categories are the allowed general categories. A single letter
+	// allows the whole group, so "L" allows Lu, Ll, Lt, Lm and Lo.
+	Categories []string `yaml:"categories" json:"categories,omitempty"`
+	// MaxBytes is the longest allowed length in bytes
+	MaxBytes int `yaml:"max_bytes" json:"max_bytes,omitempty"`
+	// MaxGraphemes is the longest allowed length in grapheme clusters
+	MaxGraphemes int `yaml:"max_graphemes" json:"max_graphemes,omitempty"`
+	// Normalization is the form strings must already be in: NFC, NFD,
+	// NFKC or NFKD
+	Normalization string `yaml:"normalization" json:"normalization,omitempty"`
+	// Forbidden lists code points, as U+202E, and ranges, as
+	// U+202A..U+202E, that may not appear
+	Forbidden []string `yaml:"forbidden" json:"forbidden,omitempty"`
+	// IDNA requires that strings convert to punycode with wtutf's "default"
+	// or "strict" conversion rules
+	IDNA string `yaml:"idna" json:"idna,omitempty"`
+}
+
+// PolicyReport is the outcome of evaluating a string against a policy. It
+// passes when every rule does.
+type PolicyReport struct {
+	Name  string         `json:"name,omitempty"`
+	Pass  bool           `json:"pass"`
+	Rules []PolicyResult `json:"rules"`
+}
+
+// PolicyResult is the outcome of one policy rule
+type PolicyResult struct {
+	Rule     string    `json:"rule"`
+	Pass     bool      `json:"pass"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+var policyNormalForms = map[string]norm.Form{
+	"NFC":  norm.NFC,
+	"NFD":  norm.NFD,
+	"NFKC": norm.NFKC,
+	"NFKD": norm.NFKD,
+}
+
+// LoadPolicy reads and validates a policy file
+func LoadPolicy(path string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := ParsePolicy(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParsePolicy reads a policy in YAML from r and validates it. Unknown keys
+// are an error, so that a misspelled rule is not silently skipped.
+func ParsePolicy(r io.Reader) (*Policy, error) {
+	p := &Policy{}
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(p); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate reports every script, block, category, normalization form, code
+// point and IDNA setting in the policy that is not recognized
+func (p *Policy) Validate() error {
+	var errs []error
+	for _, name := range p.Scripts {
+		if _, ok := unicode.Scripts[name]; !ok {
+			errs = append(errs, fmt.Errorf("unknown script %q", name))
+		}
+	}
+	if len(p.Blocks) > 0 {
+		loadUCDProperties()
+		known := map[string]bool{"No_Block": true}
+		for _, block := range blocksData {
+			known[block.value] = true
+		}
+		for _, name := range p.Blocks {
+			if !known[name] {
+				errs = append(errs, fmt.Errorf("unknown block %q", name))
+			}
+		}
+	}
+	for _, name := range p.Categories {
+		if _, ok := unicode.Categories[name]; !ok && name != "Cn" {
+			errs = append(errs, fmt.Errorf("unknown general category %q", name))
+		}
+	}
+	if p.MaxBytes < 0 || p.MaxGraphemes < 0 {
+		errs = append(errs, errors.New("maximum lengths cannot be negative"))
+	}
+	if _, ok := policyNormalForms[p.Normalization]; !ok && p.Normalization != "" {
+		errs = append(errs, fmt.Errorf("unknown normalization form %q, want one of: NFC, NFD, NFKC, NFKD", p.Normalization))
+	}
+	for _, cp := range p.Forbidden {
+		if _, _, err := parseCodePointRange(cp); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if p.IDNA != "" && p.IDNA != "default" && p.IDNA != "strict" {
+		errs = append(errs, fmt.Errorf("unknown idna rules %q, want default or strict", p.IDNA))
+	}
+	return errors.Join(errs...)
+}
+
+// parseCodePointRange parses a code point such as U+202E, or a range such as
+// U+202A..U+202E. The U+ is optional, as in the UCD data files.
+func parseCodePointRange(s string) (lo, hi rune, err error) {
+	loText, hiText, isRange := strings.Cut(s, "..")
+	parse := func(text string) (rune, error) {
+		text = strings.TrimPrefix(strings.TrimSpace(text), "U+")
+		n, err := strconv.ParseUint(text, 16, 32)
+		if err != nil || n > unicode.MaxRune {
+			return 0, fmt.Errorf("bad code point %q, want U+XXXX or U+XXXX..U+XXXX", s)
+		}
+		return rune(n), nil
+	}
+	if lo, err = parse(loText); err != nil {
+		return
+	}
+	hi = lo
+	if isRange {
+		if hi, err = parse(hiText); err != nil {
+			return
+		}
+	}
+	if hi < lo {
+		err = fmt.Errorf("bad code point range %q", s)
+	}
+	return
+}
+
+// Checks returns a Check for each rule the policy sets, named after the
+// rule's key in the policy file. The policy should be valid.
+func (p *Policy) Checks() (checks []Check) {
+	if len(p.Scripts) > 0 {
+		checks = append(checks, characterRule("scripts", func(r rune) []string {
+			scripts := FindRange(r)
+			for _, name := range scripts {
+				if name == "Common" || name == "Inherited" || slices.Contains(p.Scripts, name) {
+					return nil
+				}
+			}
+			return []string{fmt.Sprintf("%s script is not allowed", strings.Join(scripts, "+"))}
+		}))
+	}
+	if len(p.Blocks) > 0 {
+		checks = append(checks, characterRule("blocks", func(r rune) []string {
+			if block := Properties(r).Block; !slices.Contains(p.Blocks, block) {
+				return []string{fmt.Sprintf("%s block is not allowed", block)}
+			}
+			return nil
+		}))
+	}
+	if len(p.Categories) > 0 {
+		checks = append(checks, characterRule("categories", func(r rune) []string {
+			category := generalCategory(r)
+			if !slices.Contains(p.Categories, category) && !slices.Contains(p.Categories, category[:1]) {
+				return []string{fmt.Sprintf("category %s is not allowed", category)}
+			}
+			return nil
+		}))
+	}
+	if p.MaxBytes > 0 {
+		checks = append(checks, NewCheck("max_bytes", SeverityError, func(s string) []Finding {
+			if len(s) > p.MaxBytes {
+				return []Finding{{Message: fmt.Sprintf("%d bytes, more than %d", len(s), p.MaxBytes)}}
+			}
+			return nil
+		}, nil))
+	}
+	if p.MaxGraphemes > 0 {
+		checks = append(checks, NewCheck("max_graphemes", SeverityError, func(s string) []Finding {
+			if n := len(graphemeClusters(s)); n > p.MaxGraphemes {
+				return []Finding{{Message: fmt.Sprintf("%d graphemes, more than %d", n, p.MaxGraphemes)}}
+			}
+			return nil
+		}, nil))
+	}
+	if form, ok := policyNormalForms[p.Normalization]; ok {
+		checks = append(checks, NewCheck("normalization", SeverityError, func(s string) []Finding {
+			switch {
+			case !utf8.ValidString(s):
+				return []Finding{{Message: "not valid UTF-8, so not in " + p.Normalization}}
+			case !form.IsNormalString(s):
+				return []Finding{{Message: "not in " + p.Normalization}}
+			}
+			return nil
+		}, nil))
+	}
+	if len(p.Forbidden) > 0 {
+		var ranges []ucdRange
+		for _, cp := range p.Forbidden {
+			if lo, hi, err := parseCodePointRange(cp); err == nil {
+				ranges = append(ranges, ucdRange{lo, hi, cp})
+			}
+		}
+		checks = append(checks, characterRule("forbidden", func(r rune) []string {
+			for _, cr := range ranges {
+				if cr.lo <= r && r <= cr.hi {
+					return []string{fmt.Sprintf("%U %s is forbidden", r, RuneName(r))}
+				}
+			}
+			return nil
+		}))
+	}
+	if p.IDNA != "" {
+		checks = append(checks, IDNACheck(p.IDNA == "strict", false))
+	}
+	return
+}
+
+// characterRule returns a policy rule that checks each character of a string
+// with fn. Invalid UTF-8 is not a character that any rule allows, so each
+// invalid sequence fails the rule as well.
+func characterRule(name string, fn func(r rune) []string) Check {
+	return NewCheck(name, SeverityError, checkUTF8, fn)
+}
+
+// Evaluate checks s against each rule of the policy
+func (p *Policy) Evaluate(s string) *PolicyReport {
+	return p.evaluate(p.Checks(), s)
+}
+
+// evaluate runs the policy's checks over s and groups the findings by rule
+func (p *Policy) evaluate(checks []Check, s string) *PolicyReport {
+	report := &PolicyReport{Name: p.Name, Pass: true}
+	findings := RunChecks(s, checks)
+	for _, c := range checks {
+		result := PolicyResult{Rule: c.Name(), Pass: true}
+		for _, f := range findings {
+			if f.Check == c.Name() {
+				result.Findings = append(result.Findings, f)
+				result.Pass = false
+				report.Pass = false
+			}
+		}
+		report.Rules = append(report.Rules, result)
+	}
+	return report
+}
@@ -0,0 +1,132 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	input := `
+name: hostnames
+scripts: [Latin, Han]
+blocks: [Basic Latin]
+categories: [L, Nd]
+max_bytes: 63
+max_graphemes: 20
+normalization: NFC
+forbidden: [U+0131, "U+202A..U+202E"]
+idna: strict
+`
+	p, err := ParsePolicy(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParsePolicy() error = %v", err)
+	}
+	if p.Name != "hostnames" || len(p.Scripts) != 2 || p.MaxBytes != 63 || p.IDNA != "strict" {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+	var rules []string
+	for _, c := range p.Checks() {
+		rules = append(rules, c.Name())
+	}
+	if got, want := strings.Join(rules, ","), "scripts,blocks,categories,max_bytes,max_graphemes,normalization,forbidden,idna"; got != want {
+		t.Errorf("rules = %s, want %s", got, want)
+	}
+
+	if p, err := ParsePolicy(strings.NewReader("")); err != nil || len(p.Checks()) != 0 {
+		t.Errorf("expected an empty policy to have no rules, got %+v, %v", p, err)
+	}
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"misspelled rule", "script: [Latin]", "field script not found"},
+		{"unknown script", "scripts: [Latn]", `unknown script "Latn"`},
+		{"unknown block", "blocks: [Latin]", `unknown block "Latin"`},
+		{"unknown category", "categories: [Lx]", `unknown general category "Lx"`},
+		{"negative length", "max_bytes: -1", "cannot be negative"},
+		{"normalization form", "normalization: nfc", `unknown normalization form "nfc"`},
+		{"bad code point", "forbidden: [U+ZZZZ]", `bad code point "U+ZZZZ"`},
+		{"backwards range", "forbidden: [U+0041..U+0030]", `bad code point range "U+0041..U+0030"`},
+		{"idna rules", "idna: lookup", `unknown idna rules "lookup"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePolicy(strings.NewReader(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("ParsePolicy(%q) error = %v, want %q", tc.input, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		input  string
+		want   []string
+	}{
+		{"Latin username", Policy{Scripts: []string{"Latin"}}, "jane_doe1", nil},
+		{"Cyrillic letter", Policy{Scripts: []string{"Latin"}}, "jаne", []string{"byte 1: Cyrillic script is not allowed"}},
+		{"combining marks are inherited", Policy{Scripts: []string{"Latin"}}, "jose\u0301", nil},
+		{"shared script extensions", Policy{Scripts: []string{"Hiragana"}}, "らーめん", nil},
+		{"block", Policy{Blocks: []string{"Basic Latin"}}, "café", []string{"byte 3: Latin-1 Supplement block is not allowed"}},
+		{"category group", Policy{Categories: []string{"L", "Nd"}}, "ab1", nil},
+		{"category", Policy{Categories: []string{"L"}}, "a b", []string{"byte 1: category Zs is not allowed"}},
+		{"max bytes", Policy{MaxBytes: 4}, "café", []string{"5 bytes, more than 4"}},
+		{"max graphemes", Policy{MaxGraphemes: 4}, "cafe\u0301", nil},
+		{"max graphemes exceeded", Policy{MaxGraphemes: 3}, "cafe\u0301", []string{"4 graphemes, more than 3"}},
+		{"normalization", Policy{Normalization: "NFC"}, "cafe\u0301", []string{"not in NFC"}},
+		{"forbidden range", Policy{Forbidden: []string{"U+202A..U+202E"}}, "ab\u202e", []string{"byte 2: U+202E RIGHT-TO-LEFT OVERRIDE is forbidden"}},
+		{"idna", Policy{IDNA: "strict"}, "a_b", []string{`idna: disallowed rune U+005F [std3]`}},
+		{"invalid UTF-8 fails the scripts", Policy{Scripts: []string{"Latin"}}, "ab\xff", []string{"byte 2: invalid UTF-8 ff (invalid byte 0xff)"}},
+		{"invalid UTF-8 fails every character rule", Policy{Blocks: []string{"Basic Latin"}, Categories: []string{"L"}, Forbidden: []string{"U+0000"}}, "a\xc0\xafb", []string{
+			"byte 1: invalid UTF-8 c0af (overlong encoding of U+002F)",
+			"byte 1: invalid UTF-8 c0af (overlong encoding of U+002F)",
+			"byte 1: invalid UTF-8 c0af (overlong encoding of U+002F)",
+		}},
+		{"invalid UTF-8 is not normalized", Policy{Normalization: "NFC"}, "ab\xff", []string{"not valid UTF-8, so not in NFC"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := tc.policy.Evaluate(tc.input)
+			var got []string
+			for _, result := range report.Rules {
+				for _, f := range result.Findings {
+					msg := f.Message
+					if f.Length > 0 {
+						msg = fmt.Sprintf("byte %d: %s", f.Offset, msg)
+					}
+					got = append(got, msg)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("Evaluate(%q) findings = %q, want %q", tc.input, got, tc.want)
+			}
+			if report.Pass != (len(tc.want) == 0) {
+				t.Errorf("Evaluate(%q) pass = %v", tc.input, report.Pass)
+			}
+		})
+	}
+}
+
+func TestAnalyzePolicy(t *testing.T) {
+	p := &Policy{Name: "usernames", Scripts: []string{"Latin"}, MaxBytes: 16}
+	data := NewAnalyzer(Options{Table: true, Policy: p}).Analyze("jаne")
+	if data.Policy == nil || data.Policy.Name != "usernames" || data.Policy.Pass {
+		t.Fatalf("Policy = %+v, want a failed usernames report", data.Policy)
+	}
+	if len(data.Policy.Rules) != 2 || !data.Policy.Rules[1].Pass {
+		t.Errorf("expected max_bytes to pass: %+v", data.Policy.Rules)
+	}
+	if got := data.Table[1].Findings; len(got) != 1 || got[0] != "scripts: Cyrillic script is not allowed" {
+		t.Errorf("row 1 findings = %q", got)
+	}
+}
@@ -0,0 +1,184 @@
+package inspect
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/secure/bidirule"
+	"golang.org/x/text/secure/precis"
+	"golang.org/x/text/unicode/norm"
+)
+
+// PRECISResult is the enforcement of one PRECIS profile on the input
+type PRECISResult struct {
+	Profile   string       `json:"profile"`
+	Output    string       `json:"output,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	ErrorCode string       `json:"error_code,omitempty"`
+	Runes     []PRECISRune `json:"runes,omitempty"`
+}
+
+// PRECISRune is a code point a profile rejected, and the RFC 8264 category
+// or rule it was rejected by
+type PRECISRune struct {
+	Offset    int    `json:"offset"`
+	CodePoint string `json:"code_point"`
+	Name      string `json:"name"`
+	Rule      string `json:"rule"`
+}
+
+// precisProfile is a named PRECIS profile
+type precisProfile struct {
+	name    string
+	profile *precis.Profile
+}
+
+// precisProfiles returns the profiles of RFC 8265, for usernames and
+// passwords, and RFC 8266, for nicknames
+func precisProfiles() []precisProfile {
+	return []precisProfile{
+		{"UsernameCaseMapped", precis.UsernameCaseMapped},
+		{"UsernameCasePreserved", precis.UsernameCasePreserved},
+		{"OpaqueString", precis.OpaqueString},
+		{"Nickname", precis.Nickname},
+	}
+}
+
+// The precis package does not export its errors, so they are told apart by
+// their messages
+var precisErrorCodes = map[string]string{
+	"precis: disallowed rune encountered":             "disallowed-rune",
+	"precis: contextual rule violated":                "context",
+	"precis: transformation resulted in empty string": "empty",
+}
+
+// classifyPRECISError returns the wtutf error code for a precis error
+func classifyPRECISError(err error) string {
+	if errors.Is(err, bidirule.ErrInvalid) {
+		return "bidi"
+	}
+	if code, ok := precisErrorCodes[err.Error()]; ok {
+		return code
+	}
+	return "unknown"
+}
+
+// PRECIS enforces each PRECIS profile on s. The profiles map case, width and
+// spaces before checking which code points are allowed, so the output is
+// the form a login service would store and compare.
+func PRECIS(s string) (results []PRECISResult) {
+	for _, p := range precisProfiles() {
+		result := PRECISResult{Profile: p.name}
+		output, err := p.profile.String(s)
+		if err == nil {
+			result.Output = output
+		} else {
+			result.Error = err.Error()
+			result.ErrorCode = classifyPRECISError(err)
+			result.Runes = precisRunes(p.profile, s, result.ErrorCode)
+		}
+		results = append(results, result)
+	}
+	return
+}
+
+// precisRunes finds the code points of s that fail the profile on their own
+// with the same error the whole string did. Bidi and empty string errors
+// are about the string as a whole, and name no code point.
+func precisRunes(p *precis.Profile, s, code string) (runes []PRECISRune) {
+	if code != "disallowed-rune" && code != "context" {
+		return nil
+	}
+	for offset, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[offset:]); size == 1 {
+				continue
+			}
+		}
+		_, err := p.String(string(r))
+		if err == nil || classifyPRECISError(err) != code {
+			continue
+		}
+		rule := "contextual rule"
+		if code == "disallowed-rune" {
+			rule = precisCategory(r)
+		}
+		runes = append(runes, PRECISRune{
+			Offset:    offset,
+			CodePoint: fmt.Sprintf("%U", r),
+			Name:      RuneName(r),
+			Rule:      rule,
+		})
+	}
+	return
+}
+
+// precisExceptions are the code points RFC 8264 disallows by exception
+var precisExceptions = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0640, Hi: 0x0640, Stride: 1},
+		{Lo: 0x07fa, Hi: 0x07fa, Stride: 1},
+		{Lo: 0x302e, Hi: 0x302f, Stride: 1},
+		{Lo: 0x3031, Hi: 0x3035, Stride: 1},
+		{Lo: 0x303b, Hi: 0x303b, Stride: 1},
+	},
+}
+
+// oldHangulJamo are the conjoining jamo, which RFC 8264 disallows so that
+// Hangul is written in precomposed syllables
+var oldHangulJamo = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x1100, Hi: 0x11ff, Stride: 1},
+		{Lo: 0xa960, Hi: 0xa97c, Stride: 1},
+		{Lo: 0xd7b0, Hi: 0xd7fb, Stride: 1},
+	},
+}
+
+// precisCategory names the RFC 8264 category that makes r disallowed. The
+// categories are tried in the order the RFC derives them, and the first
+// category a disallowed rune is in is the reason it is disallowed: a
+// fullwidth space is HasCompat rather than Spaces.
+func precisCategory(r rune) string {
+	switch {
+	case unicode.Is(precisExceptions, r):
+		return "Exceptions"
+	case generalCategory(r) == "Cn" && !unicode.Is(unicode.Noncharacter_Code_Point, r):
+		return "Unassigned"
+	case unicode.Is(unicode.Join_Control, r):
+		return "JoinControl"
+	case unicode.Is(oldHangulJamo, r):
+		return "OldHangulJamo"
+	case unicode.Is(defaultIgnorable, r) || unicode.Is(unicode.Noncharacter_Code_Point, r):
+		return "PrecisIgnorableProperties"
+	case unicode.Is(unicode.Cc, r):
+		return "Controls"
+	case norm.NFKC.String(string(r)) != string(r):
+		return "HasCompat"
+	case unicode.Is(unicode.Zs, r):
+		return "Spaces"
+	case unicode.Is(unicode.S, r):
+		return "Symbols"
+	case unicode.Is(unicode.P, r):
+		return "Punctuation"
+	case unicode.In(r, unicode.Lt, unicode.Nl, unicode.No, unicode.Me):
+		return "OtherLetterDigits"
+	}
+	return "disallowed"
+}
+
+// markPRECISRows notes on each table row the profiles that reject it
+func markPRECISRows(table []RuneTableRow, results []PRECISResult) {
+	rows := map[int]int{}
+	for i, row := range table {
+		rows[row.Offset] = i
+	}
+	for _, result := range results {
+		for _, pr := range result.Runes {
+			if i, ok := rows[pr.Offset]; ok {
+				table[i].PRECIS = append(table[i].PRECIS, result.Profile+": "+pr.Rule)
+			}
+		}
+	}
+}
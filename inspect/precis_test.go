@@ -0,0 +1,91 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPRECIS(t *testing.T) {
+	results := PRECIS("Ｊａｎｅ")
+	if len(results) != len(precisProfiles()) {
+		t.Fatalf("expected a result per profile, got %+v", results)
+	}
+	// the username profiles fold width, and only UsernameCaseMapped
+	// lowercases; OpaqueString keeps the input as it is
+	want := map[string]string{
+		"UsernameCaseMapped":    "jane",
+		"UsernameCasePreserved": "Jane",
+		"OpaqueString":          "Ｊａｎｅ",
+		"Nickname":              "Jane",
+	}
+	for _, r := range results {
+		if r.Output != want[r.Profile] || r.Error != "" {
+			t.Errorf("%s = %+v, want output %q", r.Profile, r, want[r.Profile])
+		}
+	}
+}
+
+func TestPRECISErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		profile string
+		code    string
+		runes   []PRECISRune
+	}{
+		{"Jane Doe", "UsernameCaseMapped", "disallowed-rune", []PRECISRune{{4, "U+0020", "SPACE", "Spaces"}}},
+		{"Jane Doe", "OpaqueString", "", nil},
+		{"ﬁx", "UsernameCasePreserved", "disallowed-rune", []PRECISRune{{0, "U+FB01", "LATIN SMALL LIGATURE FI", "HasCompat"}}},
+		{"a\u0007", "OpaqueString", "disallowed-rune", []PRECISRune{{1, "U+0007", "<control>", "Controls"}}},
+		{"a\u200dx", "Nickname", "context", []PRECISRune{{1, "U+200D", "ZERO WIDTH JOINER", "contextual rule"}}},
+		{"aא", "UsernameCaseMapped", "bidi", nil},
+		{"  ", "Nickname", "empty", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.profile+" "+tc.input, func(t *testing.T) {
+			for _, r := range PRECIS(tc.input) {
+				if r.Profile != tc.profile {
+					continue
+				}
+				if r.ErrorCode != tc.code || !reflect.DeepEqual(r.Runes, tc.runes) {
+					t.Errorf("%s(%q) = %+v, want %s %+v", tc.profile, tc.input, r, tc.code, tc.runes)
+				}
+			}
+		})
+	}
+}
+
+func TestPRECISCategory(t *testing.T) {
+	for r, want := range map[rune]string{
+		'ـ':      "Exceptions",
+		'\u0378': "Unassigned",
+		'\u1100': "OldHangulJamo",
+		'\u00ad': "PrecisIgnorableProperties",
+		'\ufdd0': "PrecisIgnorableProperties",
+		'\u0007': "Controls",
+		'\u3000': "HasCompat",
+		' ':      "Spaces",
+		'$':      "Symbols",
+		'!':      "Punctuation",
+		'ǅ':      "HasCompat",
+		'ᛮ':      "OtherLetterDigits",
+	} {
+		if got := precisCategory(r); got != want {
+			t.Errorf("precisCategory(%U) = %s, want %s", r, got, want)
+		}
+	}
+}
+
+func TestAnalyzePRECIS(t *testing.T) {
+	data := NewAnalyzer(Options{Table: true, PRECIS: true}).Analyze("a b")
+	if len(data.PRECIS) != 4 {
+		t.Fatalf("PRECIS = %+v", data.PRECIS)
+	}
+	want := []string{"UsernameCaseMapped: Spaces", "UsernameCasePreserved: Spaces"}
+	if got := data.Table[1].PRECIS; !reflect.DeepEqual(got, want) {
+		t.Errorf("row 1 precis = %q, want %q", got, want)
+	}
+	if got := data.Table[0].PRECIS; got != nil {
+		t.Errorf("row 0 precis = %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package inspect
+
+import (
+	"cmp"
+
+	"golang.org/x/net/idna"
+)
+
+// ProfileResult is the conversion of the input under one IDNA profile
+type ProfileResult struct {
+	Profile   string `json:"profile"`
+	ALabel    string `json:"a_label,omitempty"`
+	ULabel    string `json:"u_label,omitempty"`
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// idnaProfile is a named set of conversion rules
+type idnaProfile struct {
+	name    string
+	profile *idna.Profile
+}
+
+// idnaProfiles returns the profiles the idna package ships, in order from
+// most to least permissive, followed by the rules wtutf converts with.
+// Browsers resolve names roughly as Lookup does, while registries apply
+// rules closer to Registration.
+func idnaProfiles() []idnaProfile {
+	return []idnaProfile{
+		{"Punycode", idna.Punycode},
+		{"Lookup", idna.Lookup},
+		{"Display", idna.Display},
+		{"Registration", idna.Registration},
+		{"wtutf", idna.New(ConversionRules(false, false)...)},
+		{"wtutf --strict", idna.New(ConversionRules(true, false)...)},
+	}
+}
+
+// Profiles converts s to an A-label and a U-label under each of the
+// idnaProfiles, so the profiles that accept a name can be compared with the
+// ones that reject it
+func Profiles(s string) (results []ProfileResult) {
+	for _, p := range idnaProfiles() {
+		result := ProfileResult{Profile: p.name}
+		alabel, aerr := p.profile.ToASCII(s)
+		ulabel, uerr := p.profile.ToUnicode(s)
+		if aerr == nil {
+			result.ALabel = alabel
+		}
+		if uerr == nil {
+			result.ULabel = ulabel
+		}
+		if err := cmp.Or(aerr, uerr); err != nil {
+			result.Error = err.Error()
+			result.ErrorCode = ClassifyIDNAError(s, err).Code
+		}
+		results = append(results, result)
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package inspect
+
+import (
+	"testing"
+)
+
+func TestProfileResults(t *testing.T) {
+	results := Profiles("Faß.de")
+	if len(results) != len(idnaProfiles()) {
+		t.Fatalf("expected a result per profile, got %+v", results)
+	}
+	byName := map[string]ProfileResult{}
+	for _, r := range results {
+		byName[r.Profile] = r
+	}
+
+	// Lookup maps upper case and keeps the deviation character ß
+	if r := byName["Lookup"]; r.ALabel != "xn--fa-hia.de" || r.ULabel != "faß.de" || r.Error != "" {
+		t.Errorf("Lookup = %+v", r)
+	}
+	// Punycode applies no mapping at all
+	if r := byName["Punycode"]; r.ULabel != "Faß.de" {
+		t.Errorf("Punycode = %+v", r)
+	}
+	// Registration rejects characters that would need mapping
+	if r := byName["Registration"]; r.ALabel != "" || r.ErrorCode != "disallowed-rune" {
+		t.Errorf("Registration = %+v", r)
+	}
+}
+
+func TestProfileResultsSTD3(t *testing.T) {
+	for _, r := range Profiles("a_b.example") {
+		wantErr := r.Profile != "Punycode" && r.Profile != "wtutf"
+		if (r.Error != "") != wantErr {
+			t.Errorf("%s: error = %q, want error %v", r.Profile, r.Error, wantErr)
+		}
+	}
+}
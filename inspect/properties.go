@@ -0,0 +1,194 @@
+package inspect
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+	"golang.org/x/text/unicode/runenames"
+	"golang.org/x/text/width"
+)
+
+//go:embed data/Blocks.txt
+var blocksTxt string
+
+//go:embed data/DerivedAge.txt
+var derivedAgeTxt string
+
+// UCDVersion is the version of the Unicode Character Database the embedded
+// block, age and Script_Extensions data is from. It is the unicode.Version of
+// the Go release the data was generated for, so that the data agrees with
+// the general category and script tables.
+const UCDVersion = "17.0.0"
+
+// unknownProperty is the block and age of a code point that is assigned in
+// unicode.Version but is newer than the embedded UCDVersion data, which can
+// happen when a Go release moves to a new Unicode version
+const unknownProperty = "unknown"
+
+var (
+	ucdPropertiesOnce sync.Once
+	blocksData        []ucdRange
+	derivedAgeData    []ucdRange
+)
+
+// ucdRange maps a range of code points to a single property value
+type ucdRange struct {
+	lo, hi rune
+	value  string
+}
+
+// RuneProperties holds the Unicode Character Database properties of a rune.
+// Block and Age are "unknown" for characters added after UCDVersion.
+type RuneProperties struct {
+	Name           string `json:"name"`
+	Category       string `json:"category"`
+	Block          string `json:"block"`
+	BidiClass      string `json:"bidi_class"`
+	EastAsianWidth string `json:"east_asian_width"`
+	Age            string `json:"age,omitempty"`
+}
+
+// bidiClassNames are the short Bidi_Class property value aliases, indexed by
+// bidi.Class
+var bidiClassNames = map[bidi.Class]string{
+	bidi.L: "L", bidi.R: "R", bidi.EN: "EN", bidi.ES: "ES", bidi.ET: "ET",
+	bidi.AN: "AN", bidi.CS: "CS", bidi.B: "B", bidi.S: "S", bidi.WS: "WS",
+	bidi.ON: "ON", bidi.BN: "BN", bidi.NSM: "NSM", bidi.AL: "AL",
+	bidi.LRO: "LRO", bidi.RLO: "RLO", bidi.LRE: "LRE", bidi.RLE: "RLE",
+	bidi.PDF: "PDF", bidi.LRI: "LRI", bidi.RLI: "RLI", bidi.FSI: "FSI",
+	bidi.PDI: "PDI",
+}
+
+// eastAsianWidthNames are the short East_Asian_Width property value aliases
+var eastAsianWidthNames = map[width.Kind]string{
+	width.Neutral:            "N",
+	width.EastAsianAmbiguous: "A",
+	width.EastAsianWide:      "W",
+	width.EastAsianNarrow:    "Na",
+	width.EastAsianFullwidth: "F",
+	width.EastAsianHalfwidth: "H",
+}
+
+// loadUCDProperties parses the embedded Blocks.txt and DerivedAge.txt data on
+// first use
+func loadUCDProperties() {
+	ucdPropertiesOnce.Do(func() {
+		var err error
+		if blocksData, err = parseUCDValues(blocksTxt); err != nil {
+			panic("wtutf: bad embedded blocks data: " + err.Error())
+		}
+		if derivedAgeData, err = parseUCDValues(derivedAgeTxt); err != nil {
+			panic("wtutf: bad embedded age data: " + err.Error())
+		}
+	})
+}
+
+// parseUCDValues reads a UCD file with a single value per code point range
+func parseUCDValues(data string) ([]ucdRange, error) {
+	var ranges []ucdRange
+	err := parseUCDRanges(strings.NewReader(data), func(lo, hi rune, value string) {
+		ranges = append(ranges, ucdRange{lo, hi, value})
+	})
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+	return ranges, err
+}
+
+// lookupUCDRange returns the value of the range containing r, or "" if there
+// is none
+func lookupUCDRange(ranges []ucdRange, r rune) string {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].hi >= r })
+	if i < len(ranges) && ranges[i].lo <= r {
+		return ranges[i].value
+	}
+	return ""
+}
+
+// Properties looks up the name, general category, block, bidi class,
+// East Asian width and age of a rune
+func Properties(r rune) *RuneProperties {
+	loadUCDProperties()
+	category := generalCategory(r)
+	block := lookupUCDRange(blocksData, r)
+	age := lookupUCDRange(derivedAgeData, r)
+	// a code point assigned since UCDVersion has no age in the embedded
+	// data, and may be in a block the data does not have
+	if age == "" && category != "Cn" {
+		age = unknownProperty
+		if block == "" {
+			block = unknownProperty
+		}
+	}
+	if block == "" {
+		block = "No_Block"
+	}
+	bidiProps, _ := bidi.LookupRune(r)
+	return &RuneProperties{
+		Name:           RuneName(r),
+		Category:       category,
+		Block:          block,
+		BidiClass:      bidiClassNames[bidiProps.Class()],
+		EastAsianWidth: eastAsianWidthNames[width.LookupRune(r).Kind()],
+		Age:            age,
+	}
+}
+
+// RuneName returns the Unicode name of a rune. Names that the data file gives
+// as a label for a whole range, such as <CJK Ideograph>, are derived with the
+// UAX #44 name derivation rules where the range has them.
+func RuneName(r rune) string {
+	name := runenames.Name(r)
+	switch {
+	case strings.HasPrefix(name, "<CJK Ideograph"):
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", r)
+	case strings.HasPrefix(name, "<Tangut Ideograph"):
+		return fmt.Sprintf("TANGUT IDEOGRAPH-%04X", r)
+	case name == "<Hangul Syllable>":
+		return hangulSyllableName(r)
+	}
+	return name
+}
+
+// Jamo short names used to derive Hangul syllable names, from the Unicode
+// Standard section 3.12
+var (
+	jamoL = []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+	jamoV = []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+	jamoT = []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+)
+
+// hangulSyllableName derives the name of a precomposed Hangul syllable from
+// its leading consonant, vowel and trailing consonant
+func hangulSyllableName(r rune) string {
+	s := int(r - 0xAC00)
+	l, v, t := s/(21*28), s%(21*28)/28, s%28
+	return "HANGUL SYLLABLE " + jamoL[l] + jamoV[v] + jamoT[t]
+}
+
+// generalCategory returns the two letter General_Category of a rune, or Cn
+// for unassigned code points
+func generalCategory(r rune) string {
+	for name, table := range unicode.Categories {
+		if len(name) == 2 && name != "LC" && unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "Cn"
+}
+
+// addRuneProperties sets the Unicode properties of every valid rune in the
+// table. Offsets in the table index into s.
+func addRuneProperties(table []RuneTableRow, s string) {
+	for i := range table {
+		if table[i].Invalid != "" {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(s[table[i].Offset:])
+		table[i].RuneProperties = Properties(r)
+	}
+}
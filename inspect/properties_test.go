@@ -0,0 +1,79 @@
+package inspect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestRuneProperties(t *testing.T) {
+	tests := []struct {
+		name string
+		r    rune
+		want RuneProperties
+	}{
+		{"ASCII letter", 'a', RuneProperties{"LATIN SMALL LETTER A", "Ll", "Basic Latin", "L", "Na", "1.1"}},
+		{"sharp s", 'ß', RuneProperties{"LATIN SMALL LETTER SHARP S", "Ll", "Latin-1 Supplement", "L", "A", "1.1"}},
+		{"combining acute accent", 0x0301, RuneProperties{"COMBINING ACUTE ACCENT", "Mn", "Combining Diacritical Marks", "NSM", "A", "1.1"}},
+		{"Arabic letter", 'ب', RuneProperties{"ARABIC LETTER BEH", "Lo", "Arabic", "AL", "N", "1.1"}},
+		{"right-to-left override", 0x202E, RuneProperties{"RIGHT-TO-LEFT OVERRIDE", "Cf", "General Punctuation", "RLO", "N", "1.1"}},
+		{"CJK ideograph", 0x4E00, RuneProperties{"CJK UNIFIED IDEOGRAPH-4E00", "Lo", "CJK Unified Ideographs", "L", "W", "1.1"}},
+		{"Hangul syllable", 0xD55C, RuneProperties{"HANGUL SYLLABLE HAN", "Lo", "Hangul Syllables", "L", "W", "2.0"}},
+		{"emoji", 0x1F600, RuneProperties{"GRINNING FACE", "So", "Emoticons", "ON", "W", "6.1"}},
+		{"unassigned", 0x0378, RuneProperties{"", "Cn", "Greek and Coptic", "L", "N", ""}},
+		// assigned in Unicode 15.0, after the embedded UCD data
+		{"added in Unicode 15.0", 0x1E030, RuneProperties{"MODIFIER LETTER CYRILLIC SMALL A", "Lm", "Cyrillic Extended-D", "L", "N", "15.0"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Properties(tc.r); !reflect.DeepEqual(*got, tc.want) {
+				t.Errorf("Properties(%U) = %+v, want %+v", tc.r, *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUCDVersion(t *testing.T) {
+	if UCDVersion != unicode.Version {
+		t.Errorf("the embedded data is from UCD %s, but the unicode package is %s", UCDVersion, unicode.Version)
+	}
+	for name, data := range map[string]string{
+		"Blocks.txt":           blocksTxt,
+		"DerivedAge.txt":       derivedAgeTxt,
+		"ScriptExtensions.txt": scriptExtensionsTxt,
+	} {
+		if want := "# Unicode Character Database " + UCDVersion + "\n"; !strings.Contains(data, want) {
+			t.Errorf("%s is not from UCD %s", name, UCDVersion)
+		}
+	}
+}
+
+func TestHangulSyllableName(t *testing.T) {
+	tests := map[rune]string{
+		0xAC00: "HANGUL SYLLABLE GA",
+		0xAC01: "HANGUL SYLLABLE GAG",
+		0xC544: "HANGUL SYLLABLE A",
+		0xD7A3: "HANGUL SYLLABLE HIH",
+	}
+	for r, want := range tests {
+		if got := hangulSyllableName(r); got != want {
+			t.Errorf("hangulSyllableName(%U) = %q, want %q", r, got, want)
+		}
+	}
+}
+
+func TestAddRuneProperties(t *testing.T) {
+	input := "a\xffé"
+	data := NewAnalyzer(Options{Table: true}).Analyze(input)
+	addRuneProperties(data.Table, input)
+	if len(data.Table) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(data.Table))
+	}
+	if data.Table[1].RuneProperties != nil {
+		t.Errorf("expected no properties for an invalid byte, got %+v", data.Table[1].RuneProperties)
+	}
+	if got := data.Table[2].Name; got != "LATIN SMALL LETTER E WITH ACUTE" {
+		t.Errorf("row 2 name = %q", got)
+	}
+}
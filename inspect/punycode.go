@@ -0,0 +1,96 @@
+package inspect
+
+import (
+	"sort"
+
+	"golang.org/x/net/idna"
+)
+
+// toPuny takes a string and a slice of []idna.Option rules and calls
+// idna.ToASCII, returning the punycode string and error
+func toPuny(s string, rules []idna.Option) (string, error) {
+	punyRules := idna.New(
+		rules...,
+	)
+	return punyRules.ToASCII(s)
+}
+
+// fromPuny takes a punycode string and returns the decoded UTF-8 string
+func fromPuny(s string, rules []idna.Option) (string, error) {
+	punyRules := idna.New(
+		rules...,
+	)
+	return punyRules.ToUnicode(s)
+}
+
+// canPunyConvert takes a rune and a slice of []idna.Option rules and attempts
+// the ToASCII conversion, then returns a bool indicating success
+func canPunyConvert(s string, rules []idna.Option) bool {
+	_, err := toPuny(s, rules)
+	return err == nil
+}
+
+// idnaRules are the punycode conversion rules that EnumerateErrors and
+// FailedRules check, each named after the option and the spec it comes from
+var idnaRules = map[string][]idna.Option{
+	"CheckBidi (RFC 5893)":                       {idna.BidiRule()},
+	"CheckJoiners (RFC 5892)":                    {idna.CheckJoiners(true)},
+	"CheckHyphens (UTS 46)":                      {idna.CheckHyphens(true)},
+	"ValidateForRegistration (RFC 5891)":         {idna.ValidateForRegistration()},
+	"ValidateLabels (RFC 5891)":                  {idna.ValidateLabels(true)},
+	"UseSTD3ASCIIRules (RFC 1034, 5891, UTS 46)": {idna.StrictDomainName(true), idna.ValidateLabels(true)},
+}
+
+// ConversionRules returns the rules used to convert the whole input, adding
+// the registration and STD3 rules in strict mode, and the transitional
+// mapping in transitional mode
+func ConversionRules(strict, transitional bool) []idna.Option {
+	rules := []idna.Option{
+		idna.BidiRule(),
+		idna.CheckJoiners(true),
+		idna.ValidateLabels(true),
+	}
+	if strict {
+		rules = append(rules,
+			idna.ValidateForRegistration(),
+			idna.StrictDomainName(true),
+		)
+	}
+	if transitional {
+		rules = append(rules, mappingRules(strict, true)...)
+	}
+	return rules
+}
+
+// mappingRules returns the rules that map the input as UTS #46 lookup does,
+// with transitional or nontransitional processing of the deviation
+// characters. The idna package only applies transitional processing in its
+// mapping step, so it has no effect without these.
+func mappingRules(strict, transitional bool) []idna.Option {
+	return []idna.Option{
+		idna.MapForLookup(),
+		idna.Transitional(transitional),
+		// MapForLookup turns on the STD3 rules, which wtutf only applies
+		// in strict mode
+		idna.StrictDomainName(strict),
+	}
+}
+
+// FailedRules takes a string, checks it against each of the idnaRules and
+// returns the names of the rules it fails, sorted
+func FailedRules(s string) []string {
+	var failed []string
+	for name, ruleset := range idnaRules {
+		if !canPunyConvert(s, ruleset) {
+			failed = append(failed, name)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// EnumerateErrors takes a rune, checks several punycode conversion rules and
+// reports the failures
+func EnumerateErrors(r rune) []string {
+	return FailedRules(string(r))
+}
@@ -1,6 +1,7 @@
-package cmd
+package inspect
 
 import (
+	"reflect"
 	"testing"
 
 	"golang.org/x/net/idna"
@@ -89,3 +90,28 @@ func TestFromPuny(t *testing.T) {
 		})
 	}
 }
+
+func TestFailedRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"valid label", "example", nil},
+		{"leading hyphen", "-example", []string{
+			"CheckHyphens (UTS 46)",
+			"UseSTD3ASCIIRules (RFC 1034, 5891, UTS 46)",
+			"ValidateForRegistration (RFC 5891)",
+			"ValidateLabels (RFC 5891)",
+		}},
+		{"underscore", "_dmarc", []string{"UseSTD3ASCIIRules (RFC 1034, 5891, UTS 46)", "ValidateForRegistration (RFC 5891)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FailedRules(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FailedRules(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
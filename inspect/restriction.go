@@ -0,0 +1,237 @@
+package inspect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// RestrictionLevel is a UTS #39 restriction level, from the most restrictive
+// (ASCIIOnly) to the least (Unrestricted)
+type RestrictionLevel int
+
+// The restriction levels of UTS #39 section 5.2
+const (
+	// ASCIIOnly strings contain only ASCII
+	ASCIIOnly RestrictionLevel = iota
+	// SingleScript strings are written in one script
+	SingleScript
+	// HighlyRestrictive strings are in one script, or in Latin and the Han
+	// combination used to write Japanese, Chinese or Korean
+	HighlyRestrictive
+	// ModeratelyRestrictive strings may also mix Latin with one other
+	// recommended script, except Cyrillic and Greek
+	ModeratelyRestrictive
+	// MinimallyRestrictive strings may mix any scripts, but only use
+	// characters of the identifier profile
+	MinimallyRestrictive
+	// Unrestricted strings may contain characters outside the identifier
+	// profile
+	Unrestricted
+)
+
+var restrictionLevelNames = []string{
+	"ascii-only",
+	"single-script",
+	"highly-restrictive",
+	"moderately-restrictive",
+	"minimally-restrictive",
+	"unrestricted",
+}
+
+// String returns the name of the level, as ParseRestrictionLevel takes it
+func (l RestrictionLevel) String() string {
+	if l < 0 || int(l) >= len(restrictionLevelNames) {
+		return fmt.Sprintf("RestrictionLevel(%d)", int(l))
+	}
+	return restrictionLevelNames[l]
+}
+
+// RestrictionLevelNames returns the names of the restriction levels, from the
+// most restrictive to the least
+func RestrictionLevelNames() []string {
+	return slices.Clone(restrictionLevelNames)
+}
+
+// ParseRestrictionLevel takes a restriction level name as printed by String
+func ParseRestrictionLevel(s string) (RestrictionLevel, error) {
+	for i, name := range restrictionLevelNames {
+		if s == name {
+			return RestrictionLevel(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown restriction level %q, want one of: %s", s, strings.Join(restrictionLevelNames, ", "))
+}
+
+// recommendedScripts are the scripts in UTS #39 Table 5, Recommended Scripts.
+// Runes from any other script are outside the identifier profile.
+var recommendedScripts = map[string]bool{
+	"Common": true, "Inherited": true, "Arabic": true, "Armenian": true,
+	"Bengali": true, "Bopomofo": true, "Cyrillic": true, "Devanagari": true,
+	"Ethiopic": true, "Georgian": true, "Greek": true, "Gujarati": true,
+	"Gurmukhi": true, "Han": true, "Hangul": true, "Hebrew": true,
+	"Hiragana": true, "Kannada": true, "Katakana": true, "Khmer": true,
+	"Lao": true, "Latin": true, "Malayalam": true, "Myanmar": true,
+	"Oriya": true, "Sinhala": true, "Tamil": true, "Telugu": true,
+	"Thaana": true, "Thai": true, "Tibetan": true,
+}
+
+// augmentedScripts are added to a rune's script set so that the scripts used
+// together in Chinese, Japanese and Korean text resolve to a common writing
+// system: Hanb (Han with Bopomofo), Jpan (Japanese) and Kore (Korean)
+var augmentedScripts = map[string][]string{
+	"Han":      {"Hanb", "Jpan", "Kore"},
+	"Hiragana": {"Jpan"},
+	"Katakana": {"Jpan"},
+	"Hangul":   {"Kore"},
+	"Bopomofo": {"Hanb"},
+}
+
+// highlyRestrictiveSets are the script combinations UTS #39 allows at the
+// Highly Restrictive level
+var highlyRestrictiveSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// augmentedScriptSet returns the augmented Script_Extensions of a rune. A nil
+// set means the rune is Common or Inherited, which goes with every script.
+func augmentedScriptSet(r rune) map[string]bool {
+	scx := scriptExtensions(r)
+	if len(scx) == 1 && (scx[0] == "Common" || scx[0] == "Inherited") {
+		return nil
+	}
+	set := map[string]bool{}
+	for _, sc := range scx {
+		set[sc] = true
+		for _, aug := range augmentedScripts[sc] {
+			set[aug] = true
+		}
+	}
+	return set
+}
+
+// inIdentifierProfile approximates the UTS #39 General Security Profile for
+// identifiers. The full Identifier_Status data is not embedded, so a rune is
+// outside the profile when it belongs to a script that is not recommended, is
+// changed by NFKC, is a control, format, private use or unassigned code point
+// (apart from the joiners), or is a non-ASCII symbol.
+func inIdentifierProfile(r rune) bool {
+	if r < utf8.RuneSelf {
+		return !unicode.IsControl(r)
+	}
+	if r == 0x200C || r == 0x200D {
+		return true
+	}
+	if unicode.Is(unicode.C, r) || unicode.Is(unicode.S, r) || unicode.Is(defaultIgnorable, r) {
+		return false
+	}
+	if !norm.NFKC.IsNormalString(string(r)) {
+		return false
+	}
+	for _, sc := range scriptExtensions(r) {
+		if recommendedScripts[sc] {
+			return true
+		}
+	}
+	return false
+}
+
+// outsideIdentifierProfile describes the first character of s that is not in
+// the identifier profile, by its code point and name
+func outsideIdentifierProfile(s string) (string, bool) {
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return "invalid UTF-8", true
+			}
+		}
+		if !inIdentifierProfile(r) {
+			return fmt.Sprintf("%U %s", r, RuneName(r)), true
+		}
+	}
+	return "", false
+}
+
+// Restriction implements the UTS #39 section 5.2 algorithm for
+// classifying a string by the scripts it mixes
+func Restriction(s string) RestrictionLevel {
+	// a character outside the identifier profile makes any string
+	// unrestricted, ASCII controls included
+	for _, r := range s {
+		if !inIdentifierProfile(r) {
+			return Unrestricted
+		}
+	}
+	if isASCII(s) {
+		return ASCIIOnly
+	}
+	var sets []map[string]bool
+	for _, r := range s {
+		if set := augmentedScriptSet(r); set != nil {
+			sets = append(sets, set)
+		}
+	}
+
+	if len(resolvedScriptSet(sets)) > 0 || len(sets) == 0 {
+		return SingleScript
+	}
+	for _, allowed := range highlyRestrictiveSets {
+		if coveredBy(sets, allowed...) {
+			return HighlyRestrictive
+		}
+	}
+	for _, set := range sets {
+		for sc := range set {
+			if sc == "Latin" || sc == "Cyrillic" || sc == "Greek" || !recommendedScripts[sc] {
+				continue
+			}
+			if coveredBy(sets, "Latin", sc) {
+				return ModeratelyRestrictive
+			}
+		}
+	}
+	return MinimallyRestrictive
+}
+
+// resolvedScriptSet returns the intersection of the script sets
+func resolvedScriptSet(sets []map[string]bool) map[string]bool {
+	if len(sets) == 0 {
+		return nil
+	}
+	resolved := map[string]bool{}
+	for sc := range sets[0] {
+		resolved[sc] = true
+	}
+	for _, set := range sets[1:] {
+		for sc := range resolved {
+			if !set[sc] {
+				delete(resolved, sc)
+			}
+		}
+	}
+	return resolved
+}
+
+// coveredBy reports whether every script set shares at least one script with
+// the given scripts
+func coveredBy(sets []map[string]bool, scripts ...string) bool {
+	for _, set := range sets {
+		found := false
+		for _, sc := range scripts {
+			if set[sc] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package inspect
+
+import (
+	"testing"
+)
+
+func TestRestrictionLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  RestrictionLevel
+	}{
+		{"ASCII", "www.google.com", ASCIIOnly},
+		{"single script Latin", "piñata", SingleScript},
+		{"Latin with combining mark", "piñata", SingleScript},
+		{"whole-script Cyrillic", "аррӏе", SingleScript},
+		{"Greek with a Latin TLD", "ελληνικά.gr", MinimallyRestrictive},
+		{"Japanese: Han, Hiragana, Katakana", "日本語のテキスト", SingleScript},
+		{"prolonged sound mark with Hiragana", "らーめん", SingleScript},
+		{"Japanese with Latin", "Go言語のテスト", HighlyRestrictive},
+		{"Korean with Latin", "한국어abc", HighlyRestrictive},
+		{"Latin and Armenian", "www.ցooցle.com", ModeratelyRestrictive},
+		{"Latin and Cyrillic", "www.ցooցlе.com", MinimallyRestrictive},
+		{"Latin and Greek", "aα", MinimallyRestrictive},
+		{"Arabic-Indic digits with Thaana", "ދ٣", SingleScript},
+		{"symbol outside identifier profile", "pay⁄pal", Unrestricted},
+		{"control character", "bell\a", Unrestricted},
+		{"format character", "pay‮pal", Unrestricted},
+		{"excluded script", "ᚠᚢᚦ", Unrestricted},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Restriction(tc.input); got != tc.want {
+				t.Errorf("Restriction(%q) = %s, want %s", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseRestrictionLevel(t *testing.T) {
+	for i, name := range RestrictionLevelNames() {
+		level, err := ParseRestrictionLevel(name)
+		if err != nil || level != RestrictionLevel(i) {
+			t.Errorf("ParseRestrictionLevel(%q) = %v, %v", name, level, err)
+		}
+	}
+	if _, err := ParseRestrictionLevel("strict"); err == nil {
+		t.Errorf("expected an error for an unknown level")
+	}
+}
+
+func TestRestrictionLevelString(t *testing.T) {
+	for l, want := range map[RestrictionLevel]string{ASCIIOnly: "ascii-only", Unrestricted: "unrestricted", -1: "RestrictionLevel(-1)", 6: "RestrictionLevel(6)"} {
+		if got := l.String(); got != want {
+			t.Errorf("RestrictionLevel(%d).String() = %q, want %q", int(l), got, want)
+		}
+	}
+	names := RestrictionLevelNames()
+	names[0] = "changed"
+	if ASCIIOnly.String() != "ascii-only" {
+		t.Error("changing the returned names changed the levels")
+	}
+}
@@ -0,0 +1,188 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// acePrefix marks a label as an A-label. DNS compares it case-insensitively.
+const acePrefix = "xn--"
+
+// RoundTripReport is the result of converting the input and converting the
+// result back. Stable is set when that gives back the input, label by label,
+// with A-labels in the input compared in their ASCII form. Error is the error
+// of whichever of the two conversions failed.
+type RoundTripReport struct {
+	Direction   string       `json:"direction"`
+	Converted   string       `json:"converted,omitempty"`
+	RoundTrip   string       `json:"round_trip,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Stable      bool         `json:"stable"`
+	FakeALabels []FakeALabel `json:"fake_a_labels,omitempty"`
+}
+
+// FakeALabel is a label with the xn-- prefix that is not a valid IDNA2008
+// A-label: it is not valid Punycode, it decodes to a U-label that breaks the
+// IDNA2008 rules, or it is not how that U-label is encoded
+type FakeALabel struct {
+	Offset    int    `json:"offset"`
+	Label     string `json:"label"`
+	ULabel    string `json:"u_label,omitempty"`
+	Reason    string `json:"reason"`
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+type labelSpan struct {
+	text   string
+	offset int
+}
+
+// splitLabels splits a domain name on the label separators. A trailing
+// separator, for the root label of a fully qualified name, does not make an
+// empty label.
+func splitLabels(s string) (labels []labelSpan) {
+	start := 0
+	for i := 0; i <= len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if i < len(s) && !isLabelSeparator(r) {
+			i += size
+			continue
+		}
+		if label := s[start:i]; label != "" || i < len(s) {
+			labels = append(labels, labelSpan{label, start})
+		}
+		if i == len(s) {
+			break
+		}
+		i += size
+		start = i
+	}
+	return
+}
+
+// hasACEPrefix reports whether a label starts with xn--, in any case
+func hasACEPrefix(label string) bool {
+	return len(label) >= len(acePrefix) && strings.EqualFold(label[:len(acePrefix)], acePrefix)
+}
+
+// RoundTrip converts s to punycode and back, or from punycode and back when
+// the Analyzer decodes punycode, with the Analyzer's conversion rules. It
+// returns nil when the first conversion fails and s has no fake A-labels,
+// since there is nothing to report beyond the conversion error.
+func (a *Analyzer) RoundTrip(s string) *RoundTripReport {
+	punyDecode, rules := a.opts.FromPunycode, a.rules
+	report := &RoundTripReport{Direction: "encode", FakeALabels: FakeALabels(s)}
+	forward, back := toPuny, fromPuny
+	if punyDecode {
+		report.Direction = "decode"
+		forward, back = fromPuny, toPuny
+	}
+
+	converted, err := forward(s, rules)
+	if err != nil {
+		if report.FakeALabels == nil {
+			return nil
+		}
+		report.Error = err.Error()
+		return report
+	}
+	report.Converted = converted
+	roundTrip, err := back(converted, rules)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.RoundTrip = roundTrip
+	report.Stable = sameLabels(s, roundTrip, rules)
+	return report
+}
+
+// sameLabels compares a domain name with its round trip. A-labels in the
+// original are compared with the round trip's labels in their ASCII form, and
+// other labels as they are.
+func sameLabels(original, roundTrip string, rules []idna.Option) bool {
+	a, b := splitLabels(original), splitLabels(roundTrip)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		switch {
+		case a[i].text == b[i].text:
+		case hasACEPrefix(a[i].text) || hasACEPrefix(b[i].text):
+			// one of them was converted, so compare their ASCII forms
+			x, errX := toPuny(a[i].text, rules)
+			y, errY := toPuny(b[i].text, rules)
+			if errX != nil || errY != nil || !strings.EqualFold(x, y) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FakeALabels finds the labels of s that have the xn-- prefix but are not
+// valid IDNA2008 A-labels. Such labels pass through many tools unchecked, and
+// can decode to characters that a registry would never allow.
+func FakeALabels(s string) (fakes []FakeALabel) {
+	idna2008 := ConversionRules(true, false)
+	for _, label := range splitLabels(s) {
+		if !hasACEPrefix(label.text) {
+			continue
+		}
+		fake := FakeALabel{Offset: label.offset, Label: label.text}
+		// the idna package only recognizes the prefix in lower case
+		alabel := acePrefix + label.text[len(acePrefix):]
+		ulabel, err := idna.Punycode.ToUnicode(alabel)
+		if err != nil {
+			fake.Reason = "not valid Punycode"
+			fake.ErrorCode = ClassifyIDNAError(alabel, err).Code
+			fakes = append(fakes, fake)
+			continue
+		}
+		fake.ULabel = ulabel
+		alabel, err = toPuny(ulabel, idna2008)
+		r, notLetterDigit := firstNonLetterDigit(ulabel)
+		switch {
+		case err != nil:
+			fake.Reason = fmt.Sprintf("decodes to %s, which is not valid under IDNA2008: %s", PoliteString(ulabel), err)
+			fake.ErrorCode = ClassifyIDNAError(ulabel, err).Code
+		case notLetterDigit:
+			fake.Reason = fmt.Sprintf("decodes to %s, which is not valid under IDNA2008: %U is not a letter, mark or digit", PoliteString(ulabel), r)
+			fake.ErrorCode = "disallowed-rune"
+		case !strings.EqualFold(alabel, label.text):
+			fake.Reason = fmt.Sprintf("decodes to %s, which is encoded as %s", PoliteString(ulabel), alabel)
+		default:
+			continue
+		}
+		fakes = append(fakes, fake)
+	}
+	return
+}
+
+// idna2008Exceptions are the code points outside the letters, marks and
+// digits that RFC 5892 section 2.6 still allows in labels, some of them only
+// in context, along with the hyphen and the joiners
+var idna2008Exceptions = map[rune]bool{
+	'-': true, '\u00B7': true, '\u0375': true, '\u05F3': true, '\u05F4': true,
+	'\u06FD': true, '\u06FE': true, '\u0F0B': true, '\u200C': true, '\u200D': true,
+	'\u3007': true, '\u30FB': true,
+}
+
+// firstNonLetterDigit returns the first code point of s that IDNA2008 does not
+// allow because it is not a letter, mark or digit (RFC 5892 section 2.1). The
+// idna package follows the UTS #46 table instead, which also accepts the
+// symbols IDNA2008 disallows, such as emoji.
+func firstNonLetterDigit(s string) (rune, bool) {
+	for _, r := range s {
+		if !unicode.In(r, unicode.Ll, unicode.Lo, unicode.Lm, unicode.Mn, unicode.Mc, unicode.Nd) && !idna2008Exceptions[r] {
+			return r, true
+		}
+	}
+	return 0, false
+}
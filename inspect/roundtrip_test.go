@@ -0,0 +1,81 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoundTripReport(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		punyDecode   bool
+		transitional bool
+		stable       bool
+		roundTrip    string
+	}{
+		{"ascii", "example.com", false, false, true, "example.com"},
+		{"u-label", "faß.de", false, false, true, "faß.de"},
+		{"a-label in the input", "xn--zca.faß.de", false, false, true, "ß.faß.de"},
+		{"transitional mapping", "faß.de", false, true, false, "fass.de"},
+		{"decode", "xn--fa-hia.de", true, false, true, "xn--fa-hia.de"},
+		{"decode with upper case digits", "xn--Zca.de", true, false, true, "xn--zca.de"},
+		{"fake a-label with upper case letters", "xn--Fa-hia.de", true, false, false, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report := NewAnalyzer(Options{FromPunycode: tc.punyDecode, Transitional: tc.transitional}).RoundTrip(tc.input)
+			if report == nil {
+				t.Fatalf("expected a round trip report for %q", tc.input)
+			}
+			if report.Stable != tc.stable || report.RoundTrip != tc.roundTrip {
+				t.Errorf("RoundTrip(%q) = %+v, want stable %v and round trip %q", tc.input, report, tc.stable, tc.roundTrip)
+			}
+		})
+	}
+}
+
+func TestRoundTripReportConversionError(t *testing.T) {
+	if report := NewAnalyzer(Options{}).RoundTrip("-bad"); report != nil {
+		t.Errorf("expected no report when conversion fails, got %+v", report)
+	}
+}
+
+func TestFakeALabels(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+		code  string
+	}{
+		{"valid", "xn--fa-hia.xn--55qx5d", nil, ""},
+		{"not punycode", "xn--abc-.com", []string{"xn--abc-"}, "punycode"},
+		{"decodes to ascii", "www.xn--ss-", []string{"xn--ss-"}, "punycode"},
+		{"emoji", "xn--ls8h.la", []string{"xn--ls8h"}, "disallowed-rune"},
+		{"disallowed rune", "xn--a-ecp.ru", []string{"xn--a-ecp"}, "disallowed-rune"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, fake := range FakeALabels(tc.input) {
+				got = append(got, fake.Label)
+				if fake.ErrorCode != tc.code {
+					t.Errorf("%s: error code %q, want %q (%s)", fake.Label, fake.ErrorCode, tc.code, fake.Reason)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FakeALabels(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSplitLabels(t *testing.T) {
+	got := splitLabels("a.bc。d.")
+	want := []labelSpan{{"a", 0}, {"bc", 2}, {"d", 7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitLabels = %+v, want %+v", got, want)
+	}
+}
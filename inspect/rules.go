@@ -0,0 +1,91 @@
+package inspect
+
+import "slices"
+
+// ScanRule describes one of the scan checks as a SARIF reporting descriptor.
+// Rule IDs are stable: new checks get new IDs and retired ones are not reused.
+type ScanRule struct {
+	ID    string
+	Check string
+	Name  string
+	Short string
+	Help  string
+	Level string
+}
+
+// scanRules are in rule ID order, so new rules go at the end and the index
+// of a rule does not change
+var scanRules = []ScanRule{
+	{
+		ID:    "WTUTF001",
+		Check: "bidi",
+		Name:  "UnbalancedBidiControl",
+		Short: "Unterminated or unmatched bidi control character",
+		Help:  "Explicit directional formatting characters (LRE, RLE, LRO, RLO, PDF, LRI, RLI, FSI, PDI) that are left open at the end of a line, or that close nothing, can make source code display in a different order than it is compiled. This is the Trojan Source attack, CVE-2021-42574. Remove the character, or terminate it within the same string or comment.",
+		Level: "error",
+	},
+	{
+		ID:    "WTUTF002",
+		Check: "invisible",
+		Name:  "InvisibleCharacter",
+		Short: "Invisible character",
+		Help:  "Default ignorable code points such as zero width spaces, soft hyphens and tag characters are not displayed, so two strings or identifiers that look the same can differ. Remove the character, or write it as an escape sequence if it is intended.",
+		Level: "warning",
+	},
+	{
+		ID:    "WTUTF003",
+		Check: "mixed-script",
+		Name:  "MixedScriptIdentifier",
+		Short: "Identifier mixes scripts",
+		Help:  "The identifier is less restrictive than the accepted UTS #39 restriction level: it mixes scripts in a way that ordinary text does not, or uses characters outside the identifier profile. A single look-alike letter from another script is a common way to create an identifier that looks like an existing one. See https://www.unicode.org/reports/tr39/#Restriction_Level_Detection.",
+		Level: "error",
+	},
+	{
+		ID:    "WTUTF004",
+		Check: "confusable",
+		Name:  "ConfusableIdentifier",
+		Short: "Identifier is confusable with an ASCII identifier",
+		Help:  "The identifier contains non-ASCII characters but its UTS #39 confusable skeleton is plain ASCII, so it can pass for a different, ASCII-only identifier. See https://www.unicode.org/reports/tr39/#Confusable_Detection.",
+		Level: "error",
+	},
+	{
+		ID:    "WTUTF005",
+		Check: "idna",
+		Name:  "IDNAConversionFailure",
+		Short: "Identifier does not convert to punycode",
+		Help:  "The identifier cannot be converted to punycode under the IDNA rules (RFC 5891, RFC 5892, RFC 5893, UTS #46) that wtutf checks, so it could not be registered as a domain name. The message has the error and its wtutf error code.",
+		Level: "error",
+	},
+	{
+		ID:    "WTUTF006",
+		Check: "utf8",
+		Name:  "InvalidUTF8",
+		Short: "Invalid UTF-8",
+		Help:  "The bytes are not valid UTF-8: a stray continuation byte, a truncated or overlong sequence, an encoded surrogate or a code point above U+10FFFF. Tools disagree on how to decode them, so they can hide content from review.",
+		Level: "error",
+	},
+	{
+		ID:    "WTUTF007",
+		Check: "idna-rune",
+		Name:  "IDNARuleViolation",
+		Short: "Character fails the IDNA conversion rules",
+		Help:  "The character cannot be converted to punycode under one or more of the IDNA rules (RFC 5891, RFC 5892, RFC 5893, UTS #46) that wtutf checks, so it cannot appear in a registrable domain name.",
+		Level: "note",
+	},
+}
+
+// ScanRules returns the rules of the scan checks. The index of a rule is its
+// index in SARIF output, as RuleForCheck returns it.
+func ScanRules() []ScanRule {
+	return slices.Clone(scanRules)
+}
+
+// RuleForCheck returns the rule for a scan check name
+func RuleForCheck(check string) (int, ScanRule) {
+	for i, rule := range scanRules {
+		if rule.Check == check {
+			return i, rule
+		}
+	}
+	return -1, ScanRule{}
+}
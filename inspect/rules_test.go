@@ -0,0 +1,30 @@
+package inspect
+
+import "testing"
+
+func TestScanRulesAreStable(t *testing.T) {
+	want := map[string]string{
+		"bidi":         "WTUTF001",
+		"invisible":    "WTUTF002",
+		"mixed-script": "WTUTF003",
+		"confusable":   "WTUTF004",
+		"idna":         "WTUTF005",
+		"utf8":         "WTUTF006",
+		"idna-rune":    "WTUTF007",
+	}
+	for check, id := range want {
+		if _, rule := RuleForCheck(check); rule.ID != id {
+			t.Errorf("check %q has rule ID %q, want %q", check, rule.ID, id)
+		}
+	}
+	seen := map[string]bool{}
+	for _, rule := range ScanRules() {
+		if seen[rule.ID] {
+			t.Errorf("duplicate rule ID %s", rule.ID)
+		}
+		seen[rule.ID] = true
+		if rule.Short == "" || rule.Help == "" || rule.Level == "" {
+			t.Errorf("rule %s is missing a description, help text or level", rule.ID)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ScanFinding is a suspicious code point found by the scan subcommand. Line
+// and Column are 1-based, and Column counts code points from the start of the
+// line. Check names the check that reported it and Rule its stable rule ID,
+// or the check name for checks that have no scan rule.
+type ScanFinding struct {
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Column    int      `json:"column"`
+	CodePoint string   `json:"code_point"`
+	Check     string   `json:"check"`
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Reason    string   `json:"reason"`
+}
+
+// ScanData holds the structured output of the scan subcommand
+type ScanData struct {
+	Files    int           `json:"files"`
+	Findings []ScanFinding `json:"findings"`
+}
+
+// ScanOptions selects the checks ScanLine applies. By default these are the
+// utf8, bidi, invisible and confusable checks and a mixed-script check that
+// reports identifiers less restrictive than Accept; IDNA adds the "idna-rune"
+// check of the punycode conversion rules for every non-ASCII character.
+// Checks, when set, are run instead.
+type ScanOptions struct {
+	Accept RestrictionLevel
+	IDNA   bool
+	Checks []Check
+}
+
+// checks returns the checks a scan runs
+func (o ScanOptions) checks() []Check {
+	if o.Checks != nil {
+		return o.Checks
+	}
+	var checks []Check
+	for _, name := range []string{"utf8", "bidi", "invisible"} {
+		c, _ := LookupCheck(name)
+		checks = append(checks, c)
+	}
+	if o.IDNA {
+		checks = append(checks, NewCheck("idna-rune", SeverityNote, nil, checkIDNARune))
+	}
+	confusable, _ := LookupCheck("confusable")
+	return append(checks, confusable, MixedScriptCheck(o.Accept))
+}
+
+// checkIDNARune reports the conversion rules a non-ASCII character fails
+func checkIDNARune(r rune) []string {
+	if r < utf8.RuneSelf {
+		return nil
+	}
+	if errs := EnumerateErrors(r); len(errs) > 0 {
+		return []string{"fails " + strings.Join(errs, ", ")}
+	}
+	return nil
+}
+
+// ScanTree walks root and appends the findings of every text file that passes
+// the include and exclude globs to data
+func ScanTree(root string, include, exclude []string, opts ScanOptions, data *ScanData) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		if rel == "." && !d.IsDir() {
+			// a file given on the command line matches by its name
+			rel = filepath.Base(p)
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && matchesGlob(rel, exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		if len(include) > 0 && !matchesGlob(rel, include) {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if isBinary(content) {
+			return nil
+		}
+		data.Files++
+		data.Findings = append(data.Findings, ScanFile(p, content, opts)...)
+		return nil
+	})
+}
+
+// matchesGlob reports whether any of the globs matches the base name of a
+// slash-separated relative path, or the path itself
+func matchesGlob(rel string, globs []string) bool {
+	base := path.Base(rel)
+	for _, g := range globs {
+		if ok, _ := path.Match(g, base); ok {
+			return true
+		}
+		if ok, _ := path.Match(g, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary guesses that a file is binary when its first 8000 bytes contain a
+// NUL byte, as git does
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0
+}
+
+// ScanFile checks every line of a file
+func ScanFile(name string, content []byte, opts ScanOptions) (findings []ScanFinding) {
+	text := string(content)
+	// a byte order mark at the start of a file is expected, not suspicious
+	text = strings.TrimPrefix(text, "\uFEFF")
+	for i, line := range strings.Split(text, "\n") {
+		for _, f := range ScanLine(line, opts) {
+			f.File = name
+			f.Line = i + 1
+			findings = append(findings, f)
+		}
+	}
+	return
+}
+
+// ScanLine checks a single line, which is its own bidi paragraph, and returns
+// findings without file and line numbers. Findings about part of the line are
+// reported where they are; findings about a string as a whole, such as a
+// mixed-script or confusable string, are looked for in each identifier that
+// is not plain ASCII.
+func ScanLine(line string, opts ScanOptions) (findings []ScanFinding) {
+	// columns maps the byte offset of each code point to its column
+	columns := map[int]int{}
+	col := 1
+	for i := range line {
+		columns[i] = col
+		col++
+	}
+	add := func(offset int, cp string, f Finding, reason string) {
+		ruleID := f.Check
+		if _, rule := RuleForCheck(f.Check); rule.ID != "" {
+			ruleID = rule.ID
+		}
+		findings = append(findings, ScanFinding{
+			Column:    columns[offset],
+			CodePoint: cp,
+			Check:     f.Check,
+			Rule:      ruleID,
+			Severity:  f.Severity,
+			Reason:    reason,
+		})
+	}
+
+	checks := opts.checks()
+	for _, f := range RunChecks(line, checks) {
+		if f.Length == 0 {
+			continue
+		}
+		span := line[f.Offset : f.Offset+f.Length]
+		cp := hex.EncodeToString([]byte(span))
+		if utf8.ValidString(span) {
+			cp = strings.Join(CodePoints(span), " ")
+		}
+		add(f.Offset, cp, f, f.Message)
+	}
+	for _, id := range identifiers(line) {
+		for _, f := range RunChecks(id.text, checks) {
+			// the findings about parts of the identifier were found in
+			// the line
+			if f.Length > 0 {
+				continue
+			}
+			add(id.offset, strings.Join(CodePoints(id.text), " "), f,
+				fmt.Sprintf("identifier %s: %s", PoliteString(id.text), f.Message))
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Column < findings[j].Column })
+	return
+}
+
+type identifier struct {
+	text   string
+	offset int
+}
+
+// identifiers splits a line into runs of letters, marks, digits, connector
+// punctuation and joiners, skipping the runs that are plain ASCII
+func identifiers(line string) (ids []identifier) {
+	start := -1
+	for i, r := range line + " " {
+		inIdentifier := unicode.In(r, unicode.L, unicode.M, unicode.Nd, unicode.Pc, unicode.Join_Control)
+		switch {
+		case inIdentifier && start < 0:
+			start = i
+		case !inIdentifier && start >= 0:
+			if text := line[start:i]; !isASCII(text) {
+				ids = append(ids, identifier{text, start})
+			}
+			start = -1
+		}
+	}
+	return
+}
@@ -0,0 +1,158 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestScanLine(t *testing.T) {
+	opts := ScanOptions{Accept: HighlyRestrictive}
+	tests := []struct {
+		name string
+		line string
+		want []ScanFinding
+	}{
+		{
+			name: "clean line",
+			line: `fmt.Println("héllo, 世界")`,
+		},
+		{
+			name: "Trojan Source comment",
+			line: "if user != \"admin\u202E \u2066// ok\u2069\u2066\" {",
+			want: []ScanFinding{
+				{Column: 18, CodePoint: "U+202E", Check: "bidi", Rule: "WTUTF001", Severity: SeverityError, Reason: "unterminated bidi control U+202E RIGHT-TO-LEFT OVERRIDE"},
+				{Column: 27, CodePoint: "U+2066", Check: "bidi", Rule: "WTUTF001", Severity: SeverityError, Reason: "unterminated bidi control U+2066 LEFT-TO-RIGHT ISOLATE"},
+			},
+		},
+		{
+			name: "invisible character",
+			line: "token\u200B := 1",
+			want: []ScanFinding{
+				{Column: 6, CodePoint: "U+200B", Check: "invisible", Rule: "WTUTF002", Severity: SeverityWarning, Reason: "invisible character ZERO WIDTH SPACE"},
+			},
+		},
+		{
+			name: "mixed-script identifier",
+			line: "var pаypal = 1",
+			want: []ScanFinding{
+				{Column: 5, CodePoint: "U+0070 U+0430 U+0079 U+0070 U+0061 U+006C", Check: "confusable", Rule: "WTUTF004", Severity: SeverityError, Reason: "identifier pаypal: has the ASCII-like skeleton paypal"},
+				{Column: 5, CodePoint: "U+0070 U+0430 U+0079 U+0070 U+0061 U+006C", Check: "mixed-script", Rule: "WTUTF003", Severity: SeverityError, Reason: "identifier pаypal: restriction level minimally-restrictive: Cyrillic, Latin"},
+			},
+		},
+		{
+			name: "whole-script confusable identifier",
+			line: "аррӏе()",
+			want: []ScanFinding{
+				{Column: 1, CodePoint: "U+0430 U+0440 U+0440 U+04CF U+0435", Check: "confusable", Rule: "WTUTF004", Severity: SeverityError, Reason: "identifier аррӏе: has the ASCII-like skeleton apple"},
+			},
+		},
+		{
+			name: "invalid UTF-8",
+			line: "é\xc0\xaf",
+			want: []ScanFinding{
+				{Column: 2, CodePoint: "c0af", Check: "utf8", Rule: "WTUTF006", Severity: SeverityError, Reason: "invalid UTF-8 c0af (overlong encoding of U+002F)"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ScanLine(tc.line, opts); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ScanLine(%q) = %+v, want %+v", tc.line, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScanLineIDNA(t *testing.T) {
+	got := ScanLine("a\u00ADb", ScanOptions{Accept: HighlyRestrictive, IDNA: true})
+	var checks []string
+	for _, f := range got {
+		checks = append(checks, f.Check)
+	}
+	if !reflect.DeepEqual(checks, []string{"invisible", "idna-rune"}) {
+		t.Errorf("expected invisible and idna-rune findings for a soft hyphen, got %+v", got)
+	}
+}
+
+func TestScanLineChecks(t *testing.T) {
+	digits := NewCheck("test-digits", SeverityNote, nil, func(r rune) []string {
+		if unicode.IsDigit(r) && r >= utf8.RuneSelf {
+			return []string{"non-ASCII digit"}
+		}
+		return nil
+	})
+	mixed := MixedScriptCheck(SingleScript)
+	got := ScanLine("x := ٣ + aα", ScanOptions{Checks: []Check{digits, mixed}})
+	want := []ScanFinding{
+		{Column: 6, CodePoint: "U+0663", Check: "test-digits", Rule: "test-digits", Severity: SeverityNote, Reason: "non-ASCII digit"},
+		{Column: 10, CodePoint: "U+0061 U+03B1", Check: "mixed-script", Rule: "WTUTF003", Severity: SeverityError, Reason: "identifier aα: restriction level minimally-restrictive: Greek, Latin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanLine() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScanTree(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":          "x := \"\u202E\"\n",
+		"README.md":        "zero\u200Bwidth\n",
+		"vendor/lib/a.go":  "y := \"\u2067\"\n",
+		"testdata/bin.dat": "bin\x00\u202E",
+		".git/config":      "\u202E\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		wantFiles        []string
+	}{
+		{"default excludes .git and binaries", nil, []string{".git"}, []string{"README.md", "main.go", "vendor/lib/a.go"}},
+		{"exclude a directory", nil, []string{".git", "vendor"}, []string{"README.md", "main.go"}},
+		{"include by extension", []string{"*.go"}, []string{".git"}, []string{"main.go", "vendor/lib/a.go"}},
+		{"include by path", []string{"vendor/*/*"}, []string{".git"}, []string{"vendor/lib/a.go"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := ScanData{}
+			if err := ScanTree(root, tc.include, tc.exclude, ScanOptions{Accept: HighlyRestrictive}, &data); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range data.Findings {
+				rel, _ := filepath.Rel(root, f.File)
+				got = append(got, filepath.ToSlash(rel))
+				if f.Line != 1 {
+					t.Errorf("%s: expected line 1, got %d", rel, f.Line)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.wantFiles) {
+				t.Errorf("findings in %v, want %v", got, tc.wantFiles)
+			}
+			if data.Files != len(tc.wantFiles) {
+				t.Errorf("scanned %d files, want %d", data.Files, len(tc.wantFiles))
+			}
+		})
+	}
+}
+
+func TestScanFileSkipsBOM(t *testing.T) {
+	if got := ScanFile("a.txt", []byte("\uFEFFplain\n"), ScanOptions{Accept: HighlyRestrictive}); len(got) != 0 {
+		t.Errorf("expected no findings for a leading byte order mark, got %+v", got)
+	}
+}
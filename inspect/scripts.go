@@ -0,0 +1,91 @@
+package inspect
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/ScriptExtensions.txt
+var scriptExtensionsTxt string
+
+var (
+	scriptExtensionsOnce sync.Once
+	scriptExtensionsData []scriptExtensionRange
+)
+
+// scriptExtensionRange maps a range of code points to their Script_Extensions
+type scriptExtensionRange struct {
+	lo, hi  rune
+	scripts []string
+}
+
+// loadScriptExtensions parses the embedded ScriptExtensions.txt data on
+// first use
+func loadScriptExtensions() []scriptExtensionRange {
+	scriptExtensionsOnce.Do(func() {
+		ranges, err := parseScriptExtensions(strings.NewReader(scriptExtensionsTxt))
+		if err != nil {
+			panic("wtutf: bad embedded script extensions data: " + err.Error())
+		}
+		scriptExtensionsData = ranges
+	})
+	return scriptExtensionsData
+}
+
+// parseScriptExtensions reads lines of the form
+// code point or range ; space separated script names
+func parseScriptExtensions(r io.Reader) ([]scriptExtensionRange, error) {
+	var ranges []scriptExtensionRange
+	err := parseUCDRanges(r, func(lo, hi rune, value string) {
+		ranges = append(ranges, scriptExtensionRange{lo, hi, strings.Fields(value)})
+	})
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+	return ranges, err
+}
+
+// parseUCDRanges reads a Unicode Character Database style file, calling fn
+// with the code point range and value of every data line
+func parseUCDRanges(r io.Reader, fn func(lo, hi rune, value string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		cps, value, ok := strings.Cut(line, ";")
+		if !ok {
+			continue
+		}
+		first, last, isRange := strings.Cut(strings.TrimSpace(cps), "..")
+		lo, err := strconv.ParseUint(first, 16, 32)
+		if err != nil {
+			return fmt.Errorf("bad code point in line %q", scanner.Text())
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.ParseUint(last, 16, 32); err != nil {
+				return fmt.Errorf("bad code point in line %q", scanner.Text())
+			}
+		}
+		fn(rune(lo), rune(hi), strings.TrimSpace(value))
+	}
+	return scanner.Err()
+}
+
+// scriptExtensions returns the Script_Extensions property of a rune: the set
+// of scripts the rune is used with. Runes that are not listed in the data
+// file take the value of their Script property.
+func scriptExtensions(r rune) []string {
+	ranges := loadScriptExtensions()
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].hi >= r })
+	if i < len(ranges) && ranges[i].lo <= r {
+		return ranges[i].scripts
+	}
+	if name := findScript(r); name != "" {
+		return []string{name}
+	}
+	return []string{"Unknown"}
+}
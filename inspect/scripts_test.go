@@ -0,0 +1,92 @@
+package inspect
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestScriptExtensions(t *testing.T) {
+	tests := []struct {
+		name string
+		r    rune
+		want []string
+	}{
+		{"Latin letter", 'a', []string{"Latin"}},
+		{"ASCII digit", '1', []string{"Common"}},
+		{"prolonged sound mark", 0x30FC, []string{"Hiragana", "Katakana"}},
+		{"Arabic-Indic digit", 0x0663, []string{"Arabic", "Thaana", "Yezidi"}},
+		{"combining acute accent", 0x0301, []string{"Cherokee", "Cyrillic", "Greek", "Latin", "Osage", "Sunuwar", "Tai_Le", "Todhri"}},
+		{"combining solidus overlay", 0x0338, []string{"Inherited"}},
+		{"middle dot", 0x00B7, []string{"Avestan", "Carian", "Coptic", "Duployan", "Elbasan", "Georgian", "Glagolitic", "Gunjala_Gondi", "Gothic", "Greek", "Han", "Latin", "Lydian", "Mahajani", "Old_Permic", "Shavian"}},
+		{"Cyrillic letter", 0x0435, []string{"Cyrillic"}},
+		{"ideographic comma", 0x3001, []string{"Bopomofo", "Hangul", "Han", "Hiragana", "Katakana", "Mongolian", "Yi"}},
+		{"Vedic tone mark", 0x1CD0, []string{"Bengali", "Devanagari", "Grantha", "Kannada"}},
+		{"Coptic epact digit", 0x102E1, []string{"Arabic", "Coptic"}},
+		{"combining bindu below", 0x1133B, []string{"Grantha", "Tamil"}},
+		{"unassigned", 0x0378, []string{"Unknown"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FindRange(tc.r); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindRange(%U) = %v, want %v", tc.r, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListRanges(t *testing.T) {
+	got := ListRanges("らーめん٣")
+	want := map[string]int{"Hiragana": 4, "Katakana": 1, "Arabic": 1, "Thaana": 1, "Yezidi": 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRanges() = %v, want %v", got, want)
+	}
+}
+
+func TestScriptExtensionsData(t *testing.T) {
+	ranges := loadScriptExtensions()
+	for i, sr := range ranges {
+		if i > 0 && sr.lo <= ranges[i-1].hi {
+			t.Errorf("range %04X..%04X overlaps the one before it", sr.lo, sr.hi)
+		}
+		for _, name := range sr.scripts {
+			if _, ok := unicode.Scripts[name]; !ok {
+				t.Errorf("range %04X..%04X has unknown script %q", sr.lo, sr.hi, name)
+			}
+		}
+	}
+}
+
+func TestParseScriptExtensions(t *testing.T) {
+	data := `# comment line
+064B..0655    ; Arabic Syriac
+0640          ; Adlam Arabic Mandaic # ARABIC TATWEEL
+`
+	ranges, err := parseScriptExtensions(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("parseScriptExtensions: %v", err)
+	}
+	want := []scriptExtensionRange{
+		{0x0640, 0x0640, []string{"Adlam", "Arabic", "Mandaic"}},
+		{0x064B, 0x0655, []string{"Arabic", "Syriac"}},
+	}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("parseScriptExtensions() = %+v, want %+v", ranges, want)
+	}
+
+	if _, err := parseScriptExtensions(strings.NewReader("zz ; Latn\n")); err == nil {
+		t.Errorf("expected an error for a bad code point")
+	}
+}
+
+func TestTableScripts(t *testing.T) {
+	data := NewAnalyzer(Options{Table: true}).Analyze("aー")
+	want := [][]string{{"Latin"}, {"Hiragana", "Katakana"}}
+	for i, row := range data.Table {
+		if !reflect.DeepEqual(row.Scripts, want[i]) {
+			t.Errorf("row %d scripts = %v, want %v", i, row.Scripts, want[i])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package inspect
+
+import (
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+)
+
+// InvalidSequence describes a run of bytes that is not valid UTF-8
+type InvalidSequence struct {
+	Offset int    `json:"offset"`
+	Bytes  string `json:"bytes"`
+	Reason string `json:"reason"`
+}
+
+// FindInvalidUTF8 walks a string and returns every invalid UTF-8 sequence in
+// it, with the byte offset of each sequence
+func FindInvalidUTF8(s string) (invalid []InvalidSequence) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			n, reason := classifyInvalid(s[i:])
+			invalid = append(invalid, InvalidSequence{
+				Offset: i,
+				Bytes:  hex.EncodeToString([]byte(s[i : i+n])),
+				Reason: reason,
+			})
+			size = n
+		}
+		i += size
+	}
+	return
+}
+
+// countCharacters counts the code points of s the way the table lists them:
+// each invalid UTF-8 sequence is one character, however many bytes it has
+func countCharacters(s string) (n int) {
+	for i := 0; i < len(s); n++ {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			size, _ = classifyInvalid(s[i:])
+		}
+		i += size
+	}
+	return
+}
+
+// classifyInvalid takes a string starting with an invalid UTF-8 sequence and
+// returns the length of that sequence and the reason it is invalid. Where the
+// bytes follow the UTF-8 bit pattern the whole sequence is consumed, so an
+// overlong encoding or an encoded surrogate is reported once rather than as
+// a series of unrelated bad bytes.
+func classifyInvalid(s string) (n int, reason string) {
+	lead := s[0]
+	var want int
+	var value rune
+	switch {
+	case lead < 0x80:
+		// not reachable: ASCII is always valid
+		return 1, "invalid byte"
+	case lead < 0xC0:
+		return 1, "unexpected continuation byte"
+	case lead < 0xE0:
+		want, value = 2, rune(lead&0x1F)
+	case lead < 0xF0:
+		want, value = 3, rune(lead&0x0F)
+	case lead < 0xF8:
+		want, value = 4, rune(lead&0x07)
+	default:
+		return 1, fmt.Sprintf("invalid byte 0x%02x", lead)
+	}
+
+	n = 1
+	for n < want && n < len(s) && isContinuation(s[n]) {
+		value = value<<6 | rune(s[n]&0x3F)
+		n++
+	}
+	if n < want {
+		return n, fmt.Sprintf("truncated %d-byte sequence", want)
+	}
+
+	switch {
+	case value < minRuneForLength(want):
+		return n, fmt.Sprintf("overlong encoding of U+%04X", value)
+	case 0xD800 <= value && value <= 0xDFFF:
+		return n, fmt.Sprintf("encoded surrogate U+%04X, as used by CESU-8 and WTF-8", value)
+	case value > utf8.MaxRune:
+		return n, fmt.Sprintf("code point U+%X is above U+10FFFF", value)
+	}
+	return n, "invalid sequence"
+}
+
+// isContinuation reports whether b is a UTF-8 continuation byte (10xxxxxx)
+func isContinuation(b byte) bool {
+	return b&0xC0 == 0x80
+}
+
+// minRuneForLength returns the smallest code point that needs an n-byte
+// UTF-8 encoding. Anything smaller is an overlong encoding.
+func minRuneForLength(n int) rune {
+	switch n {
+	case 2:
+		return 0x80
+	case 3:
+		return 0x800
+	}
+	return 0x10000
+}
@@ -0,0 +1,113 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []InvalidSequence
+	}{
+		{
+			name:  "valid input",
+			input: "piñata 🪅",
+			want:  nil,
+		},
+		{
+			name:  "literal replacement character is valid",
+			input: "�",
+			want:  nil,
+		},
+		{
+			name:  "unexpected continuation byte",
+			input: "a\x80b",
+			want:  []InvalidSequence{{1, "80", "unexpected continuation byte"}},
+		},
+		{
+			name:  "overlong slash",
+			input: "..\xc0\xaf",
+			want:  []InvalidSequence{{2, "c0af", "overlong encoding of U+002F"}},
+		},
+		{
+			name:  "overlong 3-byte NUL",
+			input: "\xe0\x80\x80",
+			want:  []InvalidSequence{{0, "e08080", "overlong encoding of U+0000"}},
+		},
+		{
+			name:  "CESU-8 surrogate pair",
+			input: "\xed\xa0\xbd\xed\xb8\x80",
+			want: []InvalidSequence{
+				{0, "eda0bd", "encoded surrogate U+D83D, as used by CESU-8 and WTF-8"},
+				{3, "edb880", "encoded surrogate U+DE00, as used by CESU-8 and WTF-8"},
+			},
+		},
+		{
+			name:  "truncated sequence followed by ASCII",
+			input: "\xe2\x82a",
+			want:  []InvalidSequence{{0, "e282", "truncated 3-byte sequence"}},
+		},
+		{
+			name:  "truncated at end of input",
+			input: "a\xf0\x9f\x94",
+			want:  []InvalidSequence{{1, "f09f94", "truncated 4-byte sequence"}},
+		},
+		{
+			name:  "above U+10FFFF",
+			input: "\xf4\x90\x80\x80",
+			want:  []InvalidSequence{{0, "f4908080", "code point U+110000 is above U+10FFFF"}},
+		},
+		{
+			name:  "invalid byte",
+			input: "\xff",
+			want:  []InvalidSequence{{0, "ff", "invalid byte 0xff"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := FindInvalidUTF8(tc.input)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindInvalidUTF8(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTableShowsInvalidBytes(t *testing.T) {
+	data := NewAnalyzer(Options{Table: true}).Analyze("a\xc0\xafb")
+	if len(data.Table) != 3 {
+		t.Fatalf("expected 3 table rows, got %d: %+v", len(data.Table), data.Table)
+	}
+	row := data.Table[1]
+	if row.Offset != 1 || row.Bytes != "c0af" || row.Length != 2 || row.Invalid == "" {
+		t.Errorf("unexpected row for invalid sequence: %+v", row)
+	}
+	if data.Table[2].Offset != 3 {
+		t.Errorf("expected offset 3 after the invalid sequence, got %d", data.Table[2].Offset)
+	}
+	if data.Characters != len(data.Table) {
+		t.Errorf("Characters = %d, want one per table row (%d)", data.Characters, len(data.Table))
+	}
+}
+
+func TestCountCharacters(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"piñata", 6},
+		{"a\xc0\xafb", 3},
+		{"a\xed\xa0\x80b", 3},
+		{"\xff\xfe", 2},
+		{"a\xe2\x82", 2},
+	}
+	for _, tc := range tests {
+		if got := countCharacters(tc.input); got != tc.want {
+			t.Errorf("countCharacters(%q) = %d, want %d", tc.input, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package inspect
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/text/cases"
+)
+
+// Watchlist is a list of protected names, such as brands or domain labels,
+// that inputs are checked against for confusable look-alikes
+type Watchlist struct {
+	entries []watchlistEntry
+}
+
+type watchlistEntry struct {
+	name     string
+	folded   string
+	skeleton string
+}
+
+// WatchlistMatch reports an input, or a label of a dotted input, that is
+// confusable with a protected name. Swaps lists the runes that differ
+// between the two, with offsets relative to Matched.
+type WatchlistMatch struct {
+	Name    string   `json:"name"`
+	Matched string   `json:"matched"`
+	Offset  int      `json:"offset"`
+	Swaps   []DiffOp `json:"swaps"`
+}
+
+// LoadWatchlist reads a watchlist file with one protected name per line.
+// Blank lines and lines starting with # are ignored.
+func LoadWatchlist(path string) (*Watchlist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseWatchlist(f)
+}
+
+// ParseWatchlist reads protected names from r and precomputes their skeletons
+func ParseWatchlist(r io.Reader) (*Watchlist, error) {
+	wl := &Watchlist{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		folded := foldCase(name)
+		wl.entries = append(wl.entries, watchlistEntry{
+			name:     name,
+			folded:   folded,
+			skeleton: Skeleton(folded),
+		})
+	}
+	return wl, scanner.Err()
+}
+
+// Match compares the whole input and each of its labels, separated by any
+// of the full stops UTS #46 treats as dots, with every protected name. A
+// part matches when it has the same case-folded skeleton as a protected name
+// without being that name.
+func (wl *Watchlist) Match(s string) (matches []WatchlistMatch) {
+	if wl == nil {
+		return nil
+	}
+	parts := []labelSpan{{s, 0}}
+	if strings.ContainsFunc(s, isLabelSeparator) {
+		parts = append(parts, splitLabels(s)...)
+	}
+
+	for _, p := range parts {
+		if p.text == "" {
+			continue
+		}
+		folded := foldCase(p.text)
+		sk := Skeleton(folded)
+		for _, e := range wl.entries {
+			if sk != e.skeleton || folded == e.folded {
+				continue
+			}
+			var swaps []DiffOp
+			for _, op := range diffUnits(runeUnits(p.text), runeUnits(e.name)) {
+				if op.Op != "equal" {
+					swaps = append(swaps, op)
+				}
+			}
+			matches = append(matches, WatchlistMatch{
+				Name:    e.name,
+				Matched: p.text,
+				Offset:  p.offset,
+				Swaps:   swaps,
+			})
+		}
+	}
+	return
+}
+
+// markWatchlistRows notes on each table row which protected name the rune
+// was swapped in to imitate
+func markWatchlistRows(table []RuneTableRow, matches []WatchlistMatch) {
+	rows := map[int]int{}
+	for i, row := range table {
+		rows[row.Offset] = i
+	}
+	for _, m := range matches {
+		for _, op := range m.Swaps {
+			if op.A == "" {
+				continue
+			}
+			i, ok := rows[m.Offset+op.AOffset]
+			if !ok {
+				continue
+			}
+			note := fmt.Sprintf("%s: %s", m.Name, DescribeSwap(op))
+			table[i].Watchlist = append(table[i].Watchlist, note)
+		}
+	}
+}
+
+// DescribeSwap renders a rune-level difference as code points
+func DescribeSwap(op DiffOp) string {
+	switch op.Op {
+	case "delete":
+		return fmt.Sprintf("%s added", strings.Join(op.ACodePoint, " "))
+	case "insert":
+		return fmt.Sprintf("%s missing", strings.Join(op.BCodePoint, " "))
+	}
+	return fmt.Sprintf("%s instead of %s", strings.Join(op.ACodePoint, " "), strings.Join(op.BCodePoint, " "))
+}
+
+func foldCase(s string) string {
+	return cases.Fold().String(s)
+}
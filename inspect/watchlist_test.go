@@ -0,0 +1,90 @@
+package inspect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWatchlistMatch(t *testing.T) {
+	wl, err := ParseWatchlist(strings.NewReader("# protected names\npaypal\n\n  Apple  \n"))
+	if err != nil {
+		t.Fatalf("ParseWatchlist() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantNames []string
+		wantSwaps int
+	}{
+		{"exact name is not a spoof", "paypal", nil, 0},
+		{"case difference is not a spoof", "PayPal", nil, 0},
+		{"digit swap", "paypa1", []string{"paypal"}, 1},
+		{"Cyrillic spoof of a differently cased name", "аррӏе", []string{"Apple"}, 5},
+		{"spoofed label in a domain", "www.pаypal.com", []string{"paypal"}, 1},
+		{"ideographic full stop", "www\u3002pаypal\u3002com", []string{"paypal"}, 1},
+		{"fullwidth full stop", "pаypal\uff0ecom", []string{"paypal"}, 1},
+		{"halfwidth ideographic full stop", "pаypal\uff61com", []string{"paypal"}, 1},
+		{"unrelated", "example.com", nil, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matches := wl.Match(tc.input)
+			var names []string
+			swaps := 0
+			for _, m := range matches {
+				names = append(names, m.Name)
+				swaps += len(m.Swaps)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.wantNames, ",") {
+				t.Fatalf("Match(%q) names = %v, want %v", tc.input, names, tc.wantNames)
+			}
+			if swaps != tc.wantSwaps {
+				t.Errorf("Match(%q) swaps = %d, want %d: %+v", tc.input, swaps, tc.wantSwaps, matches)
+			}
+		})
+	}
+
+	var nilList *Watchlist
+	if m := nilList.Match("paypa1"); m != nil {
+		t.Errorf("nil watchlist should not match, got %+v", m)
+	}
+}
+
+func TestWatchlistTableRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchlist.txt")
+	if err := os.WriteFile(path, []byte("paypal\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wl, err := LoadWatchlist(path)
+	if err != nil {
+		t.Fatalf("LoadWatchlist() error = %v", err)
+	}
+
+	data := NewAnalyzer(Options{Table: true, Watchlist: wl}).Analyze("www.pаypal.com")
+	if len(data.Watchlist) != 1 || data.Watchlist[0].Offset != 4 {
+		t.Fatalf("unexpected watchlist matches: %+v", data.Watchlist)
+	}
+	for _, row := range data.Table {
+		marked := len(row.Watchlist) > 0
+		if want := row.CodePoint == "0x0430"; marked != want {
+			t.Errorf("row at offset %d (%s) marked=%v, want %v", row.Offset, row.CodePoint, marked, want)
+		}
+	}
+
+	// punycode input is matched, and its rows marked, as the decoded string
+	data = NewAnalyzer(Options{Table: true, FromPunycode: true, Watchlist: wl}).Analyze("xn--pypal-4ve")
+	if len(data.Watchlist) != 1 || data.Watchlist[0].Matched != "pаypal" {
+		t.Fatalf("unexpected watchlist matches for punycode input: %+v", data.Watchlist)
+	}
+	if got := data.Table[1].Watchlist; len(got) != 1 {
+		t.Errorf("row 1 watchlist = %q, want the Cyrillic a marked", got)
+	}
+
+	if _, err := LoadWatchlist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("expected an error for a missing watchlist file")
+	}
+}